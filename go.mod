@@ -4,6 +4,7 @@ go 1.12
 
 require (
 	github.com/alejo-lapix/multimedia-go v1.0.10
-	github.com/aws/aws-sdk-go v1.23.3
+	github.com/aws/aws-sdk-go v1.28.0
 	github.com/google/uuid v1.1.1
+	gopkg.in/go-playground/validator.v9 v9.29.1
 )
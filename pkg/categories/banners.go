@@ -0,0 +1,168 @@
+package categories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alejo-lapix/multimedia-go/banners"
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+)
+
+// ScheduledBanner is only shown between StartsAt and EndsAt,
+// an empty limit means the window is open on that side
+type ScheduledBanner struct {
+	Banner   *banners.Banner `json:"banner"`
+	StartsAt *timestamp.Time `json:"startsAt,omitempty"`
+	EndsAt   *timestamp.Time `json:"endsAt,omitempty"`
+}
+
+func (scheduled *ScheduledBanner) IsActive(at time.Time) bool {
+	if scheduled.StartsAt != nil && at.Before(scheduled.StartsAt.Time) {
+		return false
+	}
+
+	return scheduled.EndsAt == nil || at.Before(scheduled.EndsAt.Time)
+}
+
+// ActiveBanner returns the scheduled banner active at the given time,
+// when several are active the one that started last wins, otherwise
+// it falls back to the static banner of the category
+func (category *Category) ActiveBanner(at time.Time) *banners.Banner {
+	var active *ScheduledBanner
+
+	for _, scheduled := range category.Banners {
+		if scheduled == nil || scheduled.Banner == nil || !scheduled.IsActive(at) {
+			continue
+		}
+
+		if active == nil || startsAfter(scheduled, active) {
+			active = scheduled
+		}
+	}
+
+	if active != nil {
+		return active.Banner
+	}
+
+	return category.Banner
+}
+
+func startsAfter(scheduled, other *ScheduledBanner) bool {
+	if scheduled.StartsAt == nil {
+		return false
+	}
+
+	return other.StartsAt == nil || scheduled.StartsAt.After(other.StartsAt.Time)
+}
+
+func (category *Category) ScheduleBanner(banner *banners.Banner, startsAt, endsAt *time.Time) error {
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return fmt.Errorf("the banner must end after it starts")
+	}
+
+	scheduled := &ScheduledBanner{Banner: banner}
+
+	if startsAt != nil {
+		scheduled.StartsAt = timestamp.New(*startsAt)
+	}
+
+	if endsAt != nil {
+		scheduled.EndsAt = timestamp.New(*endsAt)
+	}
+
+	category.Banners = append(category.Banners, scheduled)
+
+	return nil
+}
+
+// RemoveExpiredBanners drops the scheduled banners that already ended, the empty entries are dropped too
+func (category *Category) RemoveExpiredBanners(at time.Time) int {
+	if category == nil {
+		return 0
+	}
+
+	list := make([]*ScheduledBanner, 0, len(category.Banners))
+
+	for _, scheduled := range category.Banners {
+		if scheduled == nil || scheduled.Banner == nil {
+			continue
+		}
+
+		if scheduled.EndsAt == nil || at.Before(scheduled.EndsAt.Time) {
+			list = append(list, scheduled)
+		}
+	}
+
+	removed := len(category.Banners) - len(list)
+	category.Banners = list
+
+	return removed
+}
+
+type BannerService struct {
+	Repository CategoryRepository
+	Clock      clock.Clock
+}
+
+func NewBannerService(repository CategoryRepository) *BannerService {
+	return &BannerService{Repository: repository, Clock: clock.System}
+}
+
+// ScheduleBanner adds the banner to the category, the banners that already
+// ended are dropped in the same write so the list does not keep growing
+func (service *BannerService) ScheduleBanner(categoryID *string, banner *banners.Banner, startsAt, endsAt *time.Time) (*Category, error) {
+	category, err := service.Repository.Find(categoryID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if category == nil {
+		return nil, NotFoundError{ID: *categoryID}
+	}
+
+	if err = category.ScheduleBanner(banner, startsAt, endsAt); err != nil {
+		return nil, err
+	}
+
+	category.RemoveExpiredBanners(service.Clock.Now())
+
+	if err = service.Repository.Update(category.ID, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// ResolveBanner walks up the category chain and returns the banner of
+// the closest category that has one active at the given time
+func (service *BannerService) ResolveBanner(categoryID *string, at time.Time) (*banners.Banner, error) {
+	category, err := service.Repository.Find(categoryID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if category == nil {
+		return nil, NotFoundError{ID: *categoryID}
+	}
+
+	if banner := category.ActiveBanner(at); banner != nil {
+		return banner, nil
+	}
+
+	ancestors, err := Ancestors(service.Repository, categoryID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ancestor := range ancestors {
+		if banner := ancestor.ActiveBanner(at); banner != nil {
+			return banner, nil
+		}
+	}
+
+	return nil, nil
+}
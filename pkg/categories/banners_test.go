@@ -0,0 +1,102 @@
+package categories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alejo-lapix/multimedia-go/banners"
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+)
+
+func tm(input time.Time) *timestamp.Time {
+	return timestamp.New(input)
+}
+
+func content(banner *banners.Banner) string {
+	if banner == nil || banner.HtmlContent == nil {
+		return "<nil>"
+	}
+
+	return *banner.HtmlContent
+}
+
+func TestBannerService_ResolveBanner(t *testing.T) {
+	now := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC)
+	static := &banners.Banner{HtmlContent: s("static")}
+	christmas := &banners.Banner{HtmlContent: s("christmas")}
+	sales := &banners.Banner{HtmlContent: s("sales")}
+	items := tree()
+	items[0].Banner = static
+	items[0].Banners = []*ScheduledBanner{
+		{Banner: sales, StartsAt: tm(now.AddDate(0, -1, 0))},
+		{Banner: christmas, StartsAt: tm(now.AddDate(0, 0, -5)), EndsAt: tm(now.AddDate(0, 0, 10))},
+	}
+	service := NewBannerService(newMemoryRepository(items...))
+	tests := []struct {
+		name string
+		at   time.Time
+		want *banners.Banner
+	}{
+		{name: "Takes the banner that started last", at: now, want: christmas},
+		{name: "Ignores the expired banners", at: now.AddDate(0, 1, 0), want: sales},
+		{name: "Falls back to the static banner", at: now.AddDate(-1, 0, 0), want: static},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.ResolveBanner(s("drills"), tt.at)
+			if err != nil {
+				t.Errorf("ResolveBanner() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ResolveBanner() got = %s, want %s", content(got), content(tt.want))
+			}
+		})
+	}
+}
+
+func TestCategory_RemoveExpiredBanners(t *testing.T) {
+	now := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC)
+	current := &ScheduledBanner{Banner: &banners.Banner{HtmlContent: s("sales")}, EndsAt: tm(now.AddDate(0, 0, 1))}
+	category := &Category{Banners: []*ScheduledBanner{
+		nil,
+		{Banner: nil},
+		{Banner: &banners.Banner{HtmlContent: s("christmas")}, EndsAt: tm(now.AddDate(0, 0, -1))},
+		current,
+	}}
+
+	if removed := category.RemoveExpiredBanners(now); removed != 3 || len(category.Banners) != 1 || category.Banners[0] != current {
+		t.Errorf("RemoveExpiredBanners() = %d, banners = %v, want only the current banner", removed, category.Banners)
+	}
+
+	var missing *Category
+
+	if removed := missing.RemoveExpiredBanners(now); removed != 0 {
+		t.Errorf("RemoveExpiredBanners() = %d on a nil category, want 0", removed)
+	}
+}
+
+func TestBannerService_ScheduleBanner(t *testing.T) {
+	now := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC)
+	items := tree()
+	items[2].Banners = []*ScheduledBanner{{Banner: &banners.Banner{HtmlContent: s("black friday")}, EndsAt: tm(now.AddDate(0, 0, -20))}}
+	repository := newMemoryRepository(items...)
+	service := NewBannerService(repository)
+	service.Clock = clock.NewFixedClock(now)
+	startsAt, endsAt := now.AddDate(0, 0, 1), now.AddDate(0, 0, 10)
+
+	if _, err := service.ScheduleBanner(s("drills"), &banners.Banner{HtmlContent: s("christmas")}, &endsAt, &startsAt); err == nil {
+		t.Errorf("ScheduleBanner() expected an error when the banner ends before it starts")
+	}
+
+	if _, err := service.ScheduleBanner(s("drills"), &banners.Banner{HtmlContent: s("christmas")}, &startsAt, &endsAt); err != nil {
+		t.Fatalf("ScheduleBanner() error = %v", err)
+	}
+
+	drills, _ := repository.Find(s("drills"))
+
+	if len(drills.Banners) != 1 || content(drills.Banners[0].Banner) != "christmas" || !drills.Banners[0].StartsAt.Equal(startsAt) {
+		t.Errorf("ScheduleBanner() banners = %v, want only christmas", drills.Banners)
+	}
+}
@@ -3,36 +3,48 @@ package categories
 import (
 	"github.com/alejo-lapix/multimedia-go/banners"
 	"github.com/alejo-lapix/multimedia-go/persistence"
-	"github.com/google/uuid"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
 	"time"
 )
 
 type Category struct {
-	ID               *string                       `json:"id"`
-	Name             *string                       `json:"name" validate:"required"`
-	Description      *string                       `json:"description"`
-	Multimedia       []*persistence.MultimediaItem `json:"multimedia"`
-	ParentCategoryID *string                       `json:"parentCategoryId,omitempty"`
-	IsMainCategory   *string                       `json:"isMainCategory"`
-	Visible          *bool                         `json:"visible"`
-	CreatedAt        *string                       `json:"createdAt"`
-	Banner           *banners.Banner               `json:"banner"`
+	ID                *string               `json:"id"`
+	Name              *string               `json:"name" validate:"required"`
+	Description       *string               `json:"description"`
+	Multimedia        multimedia.Collection `json:"multimedia"`
+	ParentCategoryID  *string               `json:"parentCategoryId,omitempty"`
+	IsMainCategory    *string               `json:"isMainCategory"`
+	Visible           *bool                 `json:"visible"`
+	Position          *int                  `json:"position,omitempty"`
+	ProductCount      *int64                `json:"productCount,omitempty"`
+	TotalProductCount *int64                `json:"totalProductCount,omitempty"`
+	CreatedAt         *timestamp.Time       `json:"createdAt"`
+	DeletedAt         *timestamp.Time       `json:"deletedAt,omitempty"`
+	Banner            *banners.Banner       `json:"banner"`
+	Banners           []*ScheduledBanner    `json:"banners,omitempty"`
 }
 
-func createdAt() *string {
-	createdAt := time.Now().Format(time.RFC3339)
-
-	return &createdAt
-}
-
-func NewCategory(name, description, parentCategoryID *string, visible *bool, multimedia []*persistence.MultimediaItem, banner *banners.Banner) (*Category, error) {
-	id := uuid.New().String()
+// isMainCategory returns the value stored in the isMainCategory
+// index for a category with the given parent
+func isMainCategory(parentCategoryID *string) *string {
 	isMainCategory := "y"
 
 	if parentCategoryID != nil && *parentCategoryID != "" {
 		isMainCategory = "n"
 	}
 
+	return &isMainCategory
+}
+
+// NewCategory creates the category with the DefaultFactory
+func NewCategory(name, description, parentCategoryID *string, visible *bool, multimedia []*persistence.MultimediaItem, banner *banners.Banner) (*Category, error) {
+	return DefaultFactory.NewCategory(name, description, parentCategoryID, visible, multimedia, banner)
+}
+
+func (factory *Factory) NewCategory(name, description, parentCategoryID *string, visible *bool, multimedia []*persistence.MultimediaItem, banner *banners.Banner) (*Category, error) {
+	id := factory.IDs.NewID()
+
 	category := &Category{
 		ID:               &id,
 		Name:             name,
@@ -42,30 +54,23 @@ func NewCategory(name, description, parentCategoryID *string, visible *bool, mul
 		Visible:          visible,
 		Banner:           banner,
 
-		IsMainCategory: &isMainCategory,
-		CreatedAt:      createdAt(),
+		IsMainCategory: isMainCategory(parentCategoryID),
+		CreatedAt:      timestamp.New(factory.Clock.Now()),
+	}
+
+	if err := Validate(category); err != nil {
+		return nil, err
 	}
 
 	return category, nil
 }
 
-func (category *Category) AddMultimediaItem(item *persistence.MultimediaItem) {
-	category.Multimedia = append(category.Multimedia, item)
+func (category *Category) AddMultimediaItem(item *persistence.MultimediaItem) error {
+	return category.Multimedia.Add(item)
 }
 
 func (category *Category) RemoveMultimediaItem(id *string) bool {
-	newList := make([]*persistence.MultimediaItem, len(category.Multimedia))
-	itemExists := false
-
-	for _, item := range category.Multimedia {
-		if *item.ID != *id {
-			newList = append(newList, item)
-		} else {
-			itemExists = true
-		}
-	}
-
-	return itemExists
+	return category.Multimedia.Remove(id)
 }
 
 type Commitable interface {
@@ -73,21 +78,57 @@ type Commitable interface {
 }
 
 type CategoryRepository interface {
-	// MainCategories shows only the visible categories that does
-	// not have a parent category, it's useful for the end user
-	MainCategories(limit, offset int) ([]*Category, error)
-
-	// SubCategories shows the categories related to other one
-	SubCategories(categoryID *string) ([]*Category, error)
+	// MainCategories shows the categories that does not have a parent
+	// category, the PublicAudience only gets the visible ones
+	MainCategories(audience Audience, limit, offset int) ([]*Category, error)
+
+	// SubCategories shows the categories related to other one, the
+	// PublicAudience gets nothing when the parent is not effectively
+	// visible and only the visible children otherwise
+	SubCategories(audience Audience, categoryID *string) ([]*Category, error)
 	Find(ID *string) (*Category, error)
 	FindMany(ids []*string) ([]*Category, error)
 
+	// FindByName looks for a sibling category with the given name,
+	// the comparison ignores the case and the accents
+	FindByName(parentCategoryID, name *string) (*Category, error)
+
 	// FindManyCategory should look for the parent category
 	// if its not a principal one, otherwise returns it self
 	FindMainCategory(childCategoryID *string) (*Category, error)
+	// Store and Update fail with DuplicateNameError when
+	// a sibling category already has the same name
 	Store(*Category) error
+
+	// Remove only marks the category as deleted, the rest of the methods
+	// ignore it until it is restored or purged, its name is released
 	Remove(ID *string) error
+
+	// Restore undoes Remove, it fails with DuplicateNameError
+	// when a sibling took the name of the category meanwhile
+	Restore(ID *string) error
+
+	// Trash lists the removed categories that were not purged yet
+	Trash() ([]*Category, error)
+
+	// FindInTrash returns nil when the category is not removed
+	FindInTrash(ID *string) (*Category, error)
+
+	// Purge permanently deletes the categories removed before the given time
+	Purge(before time.Time) ([]*Category, error)
 	Update(ID *string, category *Category) error
 	All() ([]*Category, error)
 	Total() (int64, error)
+
+	// SetMultimedia only replaces the multimedia of the category,
+	// the rest of the attributes are not written
+	SetMultimedia(ID *string, items multimedia.Collection) error
+
+	// AddProductCount increments atomically the direct and the
+	// recursive product counters, negative values decrement them
+	AddProductCount(ID *string, direct, total int64) error
+
+	// SetProductCount only writes both product counters, when they no longer have
+	// the values of the given category it fails with CountChangedError
+	SetProductCount(current *Category, direct, total int64) error
 }
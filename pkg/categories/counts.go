@@ -0,0 +1,226 @@
+package categories
+
+import "fmt"
+
+// ProductCountService keeps the product counters of the categories,
+// ProductCount only includes the products assigned to the category
+// and TotalProductCount includes the ones of all the descendants
+type ProductCountService struct {
+	Repository CategoryRepository
+	Products   CategoryProducts
+}
+
+func NewProductCountService(repository CategoryRepository, products CategoryProducts) *ProductCountService {
+	return &ProductCountService{
+		Repository: repository,
+		Products:   products,
+	}
+}
+
+func (service *ProductCountService) ProductAdded(categoryID *string) error {
+	return service.apply(categoryID, 1)
+}
+
+func (service *ProductCountService) ProductRemoved(categoryID *string) error {
+	return service.apply(categoryID, -1)
+}
+
+func (service *ProductCountService) ProductMoved(fromCategoryID, toCategoryID *string) error {
+	if fromCategoryID != nil && toCategoryID != nil && *fromCategoryID == *toCategoryID {
+		return nil
+	}
+
+	if err := service.apply(fromCategoryID, -1); err != nil {
+		return err
+	}
+
+	return service.apply(toCategoryID, 1)
+}
+
+// apply updates the direct counter of the category and the
+// recursive counter of the category and all of its ancestors
+func (service *ProductCountService) apply(categoryID *string, delta int64) error {
+	if categoryID == nil || *categoryID == "" {
+		return nil
+	}
+
+	if err := service.Repository.AddProductCount(categoryID, delta, delta); err != nil {
+		return err
+	}
+
+	return addToAncestors(service.Repository, categoryID, delta)
+}
+
+// addToAncestors updates only the recursive counter
+// of all the ancestors of the given category
+func addToAncestors(repository CategoryRepository, categoryID *string, delta int64) error {
+	ancestors, err := Ancestors(repository, categoryID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, ancestor := range ancestors {
+		if err = repository.AddProductCount(ancestor.ID, 0, delta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moveTotal moves the recursive counter of a subtree from the chain
+// of one category to the chain of another, empty IDs are skipped
+func moveTotal(repository CategoryRepository, fromID, toID *string, total int64) error {
+	if err := addToChain(repository, fromID, -total); err != nil {
+		return err
+	}
+
+	return addToChain(repository, toID, total)
+}
+
+func addToChain(repository CategoryRepository, categoryID *string, total int64) error {
+	if categoryID == nil || *categoryID == "" {
+		return nil
+	}
+
+	if err := repository.AddProductCount(categoryID, 0, total); err != nil {
+		return err
+	}
+
+	return addToAncestors(repository, categoryID, total)
+}
+
+// Recompute counts again the products of every category and stores the counters that
+// drifted, it returns the repaired categories, the ones whose counters changed while it
+// runs are skipped since the count is not accurate anymore, run it again to repair them
+func (service *ProductCountService) Recompute() ([]*string, error) {
+	items, err := service.Repository.All()
+
+	if err != nil {
+		return nil, err
+	}
+
+	direct := make(map[string]int64, len(items))
+	total := make(map[string]int64, len(items))
+	byID := make(map[string]*Category, len(items))
+
+	for _, item := range items {
+		byID[*item.ID] = item
+	}
+
+	for _, item := range items {
+		ids, err := service.Products.ProductIDs(item.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		count := int64(len(ids))
+		direct[*item.ID] = count
+		visited := map[string]bool{}
+
+		for current := item; current != nil; current = parentOf(byID, current) {
+			if visited[*current.ID] {
+				return nil, fmt.Errorf("the category \"%s\" is part of a cycle", *current.ID)
+			}
+
+			visited[*current.ID] = true
+			total[*current.ID] += count
+		}
+	}
+
+	repaired := make([]*string, 0)
+
+	for _, item := range items {
+		if equals(item.ProductCount, direct[*item.ID]) && equals(item.TotalProductCount, total[*item.ID]) {
+			continue
+		}
+
+		err = service.Repository.SetProductCount(item, direct[*item.ID], total[*item.ID])
+
+		if _, changed := err.(CountChangedError); changed {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		repaired = append(repaired, item.ID)
+	}
+
+	return repaired, nil
+}
+
+func parentOf(byID map[string]*Category, category *Category) *Category {
+	if category.ParentCategoryID == nil {
+		return nil
+	}
+
+	return byID[*category.ParentCategoryID]
+}
+
+func equals(current *int64, expected int64) bool {
+	if current == nil {
+		return expected == 0
+	}
+
+	return *current == expected
+}
+
+// Ancestors returns the chain of parents of the given category
+// starting from the closest one, the category is not included
+func Ancestors(repository CategoryRepository, ID *string) ([]*Category, error) {
+	result := make([]*Category, 0)
+	visited := map[string]bool{*ID: true}
+	category, err := repository.Find(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for category != nil && category.ParentCategoryID != nil && *category.ParentCategoryID != "" {
+		if visited[*category.ParentCategoryID] {
+			return nil, fmt.Errorf("the category \"%s\" is part of a cycle", *category.ParentCategoryID)
+		}
+
+		visited[*category.ParentCategoryID] = true
+		category, err = repository.Find(category.ParentCategoryID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if category != nil {
+			result = append(result, category)
+		}
+	}
+
+	return result, nil
+}
+
+// Descendants returns the whole subtree of the given category in
+// breadth-first order, the category itself is not included
+func Descendants(repository CategoryRepository, ID *string) ([]*Category, error) {
+	result := make([]*Category, 0)
+	pending := []*string{ID}
+
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+
+		children, err := repository.SubCategories(AdminAudience, current)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range children {
+			result = append(result, child)
+			pending = append(pending, child.ID)
+		}
+	}
+
+	return result, nil
+}
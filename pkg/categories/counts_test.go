@@ -0,0 +1,110 @@
+package categories
+
+import "testing"
+
+func count(input int64) *int64 {
+	return &input
+}
+
+func TestProductCountService_ProductMoved(t *testing.T) {
+	repository := newMemoryRepository(tree()...)
+	service := NewProductCountService(repository, &memoryProducts{assignments: map[string]string{}})
+
+	if err := service.ProductAdded(s("drills")); err != nil {
+		t.Fatalf("ProductAdded() error = %v", err)
+	}
+
+	if err := service.ProductMoved(s("drills"), s("other")); err != nil {
+		t.Fatalf("ProductMoved() error = %v", err)
+	}
+
+	tests := []struct {
+		ID         string
+		wantDirect int64
+		wantTotal  int64
+	}{
+		{ID: "drills", wantDirect: 0, wantTotal: 0},
+		{ID: "power", wantDirect: 0, wantTotal: 0},
+		{ID: "tools", wantDirect: 0, wantTotal: 0},
+		{ID: "other", wantDirect: 1, wantTotal: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ID, func(t *testing.T) {
+			category, _ := repository.Find(s(tt.ID))
+			if !equals(category.ProductCount, tt.wantDirect) || !equals(category.TotalProductCount, tt.wantTotal) {
+				t.Errorf("counters = %v/%v, want %d/%d", category.ProductCount, category.TotalProductCount, tt.wantDirect, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestProductCountService_Recompute(t *testing.T) {
+	items := tree()
+	items[0].TotalProductCount = count(10)
+	repository := newMemoryRepository(items...)
+	products := &memoryProducts{assignments: map[string]string{"p1": "drills", "p2": "drills", "p3": "tools"}}
+	service := NewProductCountService(repository, products)
+
+	repaired, err := service.Recompute()
+
+	if err != nil {
+		t.Fatalf("Recompute() error = %v", err)
+	}
+
+	if len(repaired) != 3 {
+		t.Errorf("Recompute() repaired = %d, want 3", len(repaired))
+	}
+
+	tools, _ := repository.Find(s("tools"))
+
+	if *tools.ProductCount != 1 || *tools.TotalProductCount != 3 {
+		t.Errorf("Recompute() tools = %d/%d, want 1/3", *tools.ProductCount, *tools.TotalProductCount)
+	}
+}
+
+// racingRepository adds a product to the category right before its counters are recomputed
+type racingRepository struct {
+	*memoryRepository
+	categoryID string
+}
+
+// All returns copies, like a real repository the counters read do not follow the writes
+func (repository *racingRepository) All() ([]*Category, error) {
+	items := make([]*Category, len(repository.items))
+
+	for index, item := range repository.items {
+		copied := *item
+		items[index] = &copied
+	}
+
+	return items, nil
+}
+
+func (repository *racingRepository) SetProductCount(current *Category, direct, total int64) error {
+	if *current.ID == repository.categoryID {
+		_ = repository.AddProductCount(current.ID, 1, 1)
+	}
+
+	return repository.memoryRepository.SetProductCount(current, direct, total)
+}
+
+func TestProductCountService_RecomputeConcurrentChange(t *testing.T) {
+	repository := &racingRepository{memoryRepository: newMemoryRepository(tree()...), categoryID: "tools"}
+	products := &memoryProducts{assignments: map[string]string{"p1": "drills", "p3": "tools"}}
+
+	repaired, err := NewProductCountService(repository, products).Recompute()
+
+	if err != nil {
+		t.Fatalf("Recompute() error = %v", err)
+	}
+
+	for _, ID := range repaired {
+		if *ID == "tools" {
+			t.Errorf("Recompute() repaired tools, want it skipped after the concurrent change")
+		}
+	}
+
+	if tools, _ := repository.Find(s("tools")); *tools.ProductCount != 1 || *tools.TotalProductCount != 1 {
+		t.Errorf("Recompute() tools = %d/%d, want the concurrent increment kept", *tools.ProductCount, *tools.TotalProductCount)
+	}
+}
@@ -0,0 +1,210 @@
+package categories
+
+import "fmt"
+
+// DeletePolicy tells the deletion service what to do with the
+// subcategories of the category being removed
+type DeletePolicy string
+
+const (
+	// RefuseDelete aborts the deletion when the category has subcategories
+	RefuseDelete DeletePolicy = "refuse"
+	// CascadeDelete removes the whole subtree of the category
+	CascadeDelete DeletePolicy = "cascade"
+	// ReparentChildren moves the subcategories to the parent of the removed category
+	ReparentChildren DeletePolicy = "reparent"
+)
+
+// CategoryProducts gives the category services access to the products
+// assigned to a category without depending on the products package
+type CategoryProducts interface {
+	ProductIDs(categoryID *string) ([]*string, error)
+	MoveProducts(productIDs []*string, categoryID *string) error
+}
+
+type DeleteOptions struct {
+	Policy DeletePolicy
+	// FallbackCategoryID receives the products of every removed
+	// category, when it is empty the deletion is refused if any
+	// of those categories still has products
+	FallbackCategoryID *string
+	// DryRun reports what would be affected without writing anything
+	DryRun bool
+}
+
+type DeletionReport struct {
+	DryRun             bool      `json:"dryRun"`
+	RemovedCategories  []*string `json:"removedCategories"`
+	ReparentedChildren []*string `json:"reparentedChildren"`
+	MovedProducts      []*string `json:"movedProducts"`
+}
+
+// CategoryInUseError is returned when the policy does not allow to
+// delete a category that still has subcategories or products
+type CategoryInUseError struct {
+	ID            string
+	SubCategories int
+	Products      int
+}
+
+func (err CategoryInUseError) Error() string {
+	return fmt.Sprintf("the category \"%s\" has %d subcategories and %d products", err.ID, err.SubCategories, err.Products)
+}
+
+type DeleteCategoryService struct {
+	Repository CategoryRepository
+	Products   CategoryProducts
+}
+
+func NewDeleteCategoryService(repository CategoryRepository, products CategoryProducts) *DeleteCategoryService {
+	return &DeleteCategoryService{
+		Repository: repository,
+		Products:   products,
+	}
+}
+
+func (service *DeleteCategoryService) Delete(ID *string, options *DeleteOptions) (*DeletionReport, error) {
+	if options == nil {
+		options = &DeleteOptions{Policy: RefuseDelete}
+	}
+
+	category, err := service.Repository.Find(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if category == nil {
+		return nil, NotFoundError{ID: *ID}
+	}
+
+	if options.FallbackCategoryID != nil {
+		if *options.FallbackCategoryID == *ID {
+			return nil, fmt.Errorf("the fallback category can not be the category being removed")
+		}
+
+		fallback, err := service.Repository.Find(options.FallbackCategoryID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if fallback == nil || fallback.DeletedAt != nil {
+			return nil, NotFoundError{ID: *options.FallbackCategoryID}
+		}
+	}
+
+	children, err := service.Repository.SubCategories(AdminAudience, ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DeletionReport{
+		DryRun:             options.DryRun,
+		RemovedCategories:  []*string{},
+		ReparentedChildren: []*string{},
+		MovedProducts:      []*string{},
+	}
+
+	removed := []*Category{category}
+
+	switch options.Policy {
+	case CascadeDelete:
+		descendants, err := Descendants(service.Repository, ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		removed = append(removed, descendants...)
+	case ReparentChildren:
+		if err = service.checkNames(category, children); err != nil {
+			return nil, err
+		}
+
+		for _, child := range children {
+			report.ReparentedChildren = append(report.ReparentedChildren, child.ID)
+		}
+	default:
+		if len(children) > 0 {
+			return nil, CategoryInUseError{ID: *ID, SubCategories: len(children)}
+		}
+	}
+
+	for _, item := range removed {
+		if options.FallbackCategoryID != nil && *options.FallbackCategoryID == *item.ID {
+			return nil, fmt.Errorf("the fallback category \"%s\" would be removed as well", *item.ID)
+		}
+
+		productIDs, err := service.Products.ProductIDs(item.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(productIDs) > 0 && options.FallbackCategoryID == nil {
+			return nil, CategoryInUseError{ID: *item.ID, Products: len(productIDs)}
+		}
+
+		report.MovedProducts = append(report.MovedProducts, productIDs...)
+		report.RemovedCategories = append(report.RemovedCategories, item.ID)
+	}
+
+	if options.DryRun {
+		return report, nil
+	}
+
+	if len(report.MovedProducts) > 0 {
+		err = service.Products.MoveProducts(report.MovedProducts, options.FallbackCategoryID)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.Policy == ReparentChildren {
+		for _, child := range children {
+			child.ParentCategoryID = category.ParentCategoryID
+			child.IsMainCategory = isMainCategory(category.ParentCategoryID)
+			// The position belongs to the previous siblings
+			child.Position = nil
+
+			if err = service.Repository.Update(child.ID, child); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Removes the deepest categories first, so an interrupted
+	// cascade never leaves a subcategory without its parent
+	for index := len(removed) - 1; index >= 0; index-- {
+		if err = service.Repository.Remove(removed[index].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// checkNames refuses to reparent the children when the parent of the category already has a
+// subcategory with the same name, the category itself counts since it is removed at the end
+func (service *DeleteCategoryService) checkNames(category *Category, children []*Category) error {
+	for _, child := range children {
+		if child.Name == nil {
+			continue
+		}
+
+		sibling, err := service.Repository.FindByName(category.ParentCategoryID, child.Name)
+
+		if err != nil {
+			return err
+		}
+
+		if sibling != nil && *sibling.ID != *child.ID {
+			return NewDuplicateNameError(category.ParentCategoryID, child.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package categories
+
+import (
+	"testing"
+)
+
+func tree() []*Category {
+	return []*Category{
+		{ID: s("tools"), Name: s("Tools")},
+		{ID: s("power"), Name: s("Power Tools"), ParentCategoryID: s("tools")},
+		{ID: s("drills"), Name: s("Drills"), ParentCategoryID: s("power")},
+		{ID: s("other"), Name: s("Other")},
+	}
+}
+
+func TestDeleteCategoryService_Delete(t *testing.T) {
+	tests := []struct {
+		name          string
+		ID            string
+		options       *DeleteOptions
+		products      map[string]string
+		wantErr       bool
+		wantRemaining int
+		wantProducts  map[string]string
+	}{
+		{
+			name:          "Refuses to delete a category with subcategories",
+			ID:            "power",
+			options:       &DeleteOptions{Policy: RefuseDelete},
+			products:      map[string]string{},
+			wantErr:       true,
+			wantRemaining: 4,
+		},
+		{
+			name:          "Refuses to delete a category with products and no fallback",
+			ID:            "drills",
+			options:       nil,
+			products:      map[string]string{"p1": "drills"},
+			wantErr:       true,
+			wantRemaining: 4,
+		},
+		{
+			name:          "Cascades to the whole subtree moving the products",
+			ID:            "tools",
+			options:       &DeleteOptions{Policy: CascadeDelete, FallbackCategoryID: s("other")},
+			products:      map[string]string{"p1": "drills", "p2": "tools"},
+			wantRemaining: 1,
+			wantProducts:  map[string]string{"p1": "other", "p2": "other"},
+		},
+		{
+			name:          "Refuses a missing fallback category",
+			ID:            "drills",
+			options:       &DeleteOptions{FallbackCategoryID: s("missing")},
+			products:      map[string]string{"p1": "drills"},
+			wantErr:       true,
+			wantRemaining: 4,
+			wantProducts:  map[string]string{"p1": "drills"},
+		},
+		{
+			name:          "Reparents the children to the grandparent",
+			ID:            "power",
+			options:       &DeleteOptions{Policy: ReparentChildren},
+			products:      map[string]string{},
+			wantRemaining: 3,
+		},
+		{
+			name:          "Dry run does not write anything",
+			ID:            "tools",
+			options:       &DeleteOptions{Policy: CascadeDelete, FallbackCategoryID: s("other"), DryRun: true},
+			products:      map[string]string{"p1": "drills"},
+			wantRemaining: 4,
+			wantProducts:  map[string]string{"p1": "drills"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newMemoryRepository(tree()...)
+			products := &memoryProducts{assignments: tt.products}
+			service := NewDeleteCategoryService(repository, products)
+
+			_, err := service.Delete(s(tt.ID), tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(repository.items) != tt.wantRemaining {
+				t.Errorf("Delete() remaining = %d, want %d", len(repository.items), tt.wantRemaining)
+			}
+			for product, category := range tt.wantProducts {
+				if products.assignments[product] != category {
+					t.Errorf("Delete() product %s in %s, want %s", product, products.assignments[product], category)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteCategoryService_DeleteReparent(t *testing.T) {
+	items := tree()
+	position := 3
+	items[2].Position = &position
+	repository := newMemoryRepository(items...)
+	service := NewDeleteCategoryService(repository, &memoryProducts{assignments: map[string]string{}})
+
+	if _, err := service.Delete(s("power"), &DeleteOptions{Policy: ReparentChildren}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	drills, _ := repository.Find(s("drills"))
+
+	if *drills.ParentCategoryID != "tools" || drills.Position != nil {
+		t.Errorf("Delete() parent = %s, position = %v, want tools without position", *drills.ParentCategoryID, drills.Position)
+	}
+}
+
+func TestDeleteCategoryService_DeleteReparentDuplicatedName(t *testing.T) {
+	for _, dryRun := range []bool{true, false} {
+		items := append(tree(), &Category{ID: s("other-drills"), Name: s("DRILLS"), ParentCategoryID: s("tools")})
+		repository := newMemoryRepository(items...)
+		products := &memoryProducts{assignments: map[string]string{"p1": "power"}}
+		service := NewDeleteCategoryService(repository, products)
+
+		_, err := service.Delete(s("power"), &DeleteOptions{Policy: ReparentChildren, FallbackCategoryID: s("other"), DryRun: dryRun})
+
+		if _, ok := err.(DuplicateNameError); !ok {
+			t.Errorf("Delete() dry run %v error = %v, want a DuplicateNameError", dryRun, err)
+		}
+
+		if power, _ := repository.Find(s("power")); power == nil || products.assignments["p1"] != "power" {
+			t.Errorf("Delete() dry run %v wrote before failing", dryRun)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package categories
+
+import "fmt"
+
+// NotFoundError is returned when the requested category does not exist
+type NotFoundError struct {
+	ID string
+}
+
+func (err NotFoundError) Error() string {
+	return fmt.Sprintf("the category \"%s\" does not exist", err.ID)
+}
+
+// CountChangedError is returned when the product counters of the
+// category changed between the moment they were read and the write
+type CountChangedError struct {
+	ID string
+}
+
+func (err CountChangedError) Error() string {
+	return fmt.Sprintf("the product counters of the category \"%s\" changed while they were written", err.ID)
+}
@@ -0,0 +1,18 @@
+package categories
+
+import (
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/ids"
+)
+
+// Factory creates the categories with the configured ID strategy and clock
+type Factory struct {
+	ids.Factory
+}
+
+func NewFactory(generator ids.Generator, clock clock.Clock) *Factory {
+	return &Factory{Factory: ids.Factory{IDs: generator, Clock: clock}}
+}
+
+// DefaultFactory uses random UUIDs and the system clock
+var DefaultFactory = NewFactory(ids.UUIDGenerator{}, clock.System)
@@ -0,0 +1,290 @@
+package categories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/ids"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+)
+
+func testFactory() *Factory {
+	return NewFactory(&ids.Sequence{}, clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func s(input string) *string {
+	return &input
+}
+
+func b(input bool) *bool {
+	return &input
+}
+
+type memoryRepository struct {
+	items []*Category
+	trash []*Category
+	clock clock.Clock
+}
+
+func newMemoryRepository(items ...*Category) *memoryRepository {
+	return &memoryRepository{items: items, clock: clock.System}
+}
+
+func (repository *memoryRepository) MainCategories(audience Audience, limit, offset int) ([]*Category, error) {
+	result := make([]*Category, 0)
+
+	for _, item := range repository.items {
+		if audience == PublicAudience && !item.IsVisible() {
+			continue
+		}
+
+		if item.ParentCategoryID == nil || *item.ParentCategoryID == "" {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (repository *memoryRepository) SubCategories(audience Audience, categoryID *string) ([]*Category, error) {
+	result := make([]*Category, 0)
+
+	if audience == PublicAudience {
+		visible, err := IsEffectivelyVisible(repository, categoryID)
+
+		if err != nil || !visible {
+			return result, err
+		}
+	}
+
+	for _, item := range repository.items {
+		if audience == PublicAudience && !item.IsVisible() {
+			continue
+		}
+
+		if item.ParentCategoryID != nil && *item.ParentCategoryID == *categoryID {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (repository *memoryRepository) Find(ID *string) (*Category, error) {
+	for _, item := range repository.items {
+		if *item.ID == *ID {
+			return item, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (repository *memoryRepository) FindMany(ids []*string) ([]*Category, error) {
+	result := make([]*Category, 0)
+
+	for _, id := range ids {
+		item, _ := repository.Find(id)
+
+		if item != nil {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (repository *memoryRepository) FindByName(parentCategoryID, name *string) (*Category, error) {
+	for _, item := range repository.items {
+		if item.Name != nil && NameKey(item.ParentCategoryID, *item.Name) == NameKey(parentCategoryID, *name) {
+			return item, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (repository *memoryRepository) FindMainCategory(childCategoryID *string) (*Category, error) {
+	category, _ := repository.Find(childCategoryID)
+
+	if category == nil {
+		return nil, fmt.Errorf("not found")
+	}
+
+	if category.ParentCategoryID == nil || *category.ParentCategoryID == "" {
+		return category, nil
+	}
+
+	return repository.FindMainCategory(category.ParentCategoryID)
+}
+
+func (repository *memoryRepository) Store(category *Category) error {
+	repository.items = append(repository.items, category)
+
+	return nil
+}
+
+func (repository *memoryRepository) Remove(ID *string) error {
+	for index, item := range repository.items {
+		if *item.ID == *ID {
+			repository.items = append(repository.items[:index], repository.items[index+1:]...)
+			item.DeletedAt = timestamp.New(repository.clock.Now())
+			repository.trash = append(repository.trash, item)
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (repository *memoryRepository) Restore(ID *string) error {
+	for index, item := range repository.trash {
+		if *item.ID == *ID {
+			if sibling, _ := repository.FindByName(item.ParentCategoryID, item.Name); sibling != nil {
+				return NewDuplicateNameError(item.ParentCategoryID, item.Name)
+			}
+
+			repository.trash = append(repository.trash[:index], repository.trash[index+1:]...)
+			item.DeletedAt = nil
+			repository.items = append(repository.items, item)
+
+			return nil
+		}
+	}
+
+	return NotFoundError{ID: *ID}
+}
+
+func (repository *memoryRepository) Trash() ([]*Category, error) {
+	return append([]*Category{}, repository.trash...), nil
+}
+
+func (repository *memoryRepository) FindInTrash(ID *string) (*Category, error) {
+	for _, item := range repository.trash {
+		if *item.ID == *ID {
+			return item, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (repository *memoryRepository) Purge(before time.Time) ([]*Category, error) {
+	purged := make([]*Category, 0)
+	kept := make([]*Category, 0)
+
+	for _, item := range repository.trash {
+		if item.DeletedAt.Before(before) {
+			purged = append(purged, item)
+		} else {
+			kept = append(kept, item)
+		}
+	}
+
+	repository.trash = kept
+
+	return purged, nil
+}
+
+func (repository *memoryRepository) Update(ID *string, category *Category) error {
+	for index, item := range repository.items {
+		if *item.ID == *ID {
+			repository.items[index] = category
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("not found")
+}
+
+func (repository *memoryRepository) All() ([]*Category, error) {
+	return repository.items, nil
+}
+
+func (repository *memoryRepository) SetProductCount(current *Category, direct, total int64) error {
+	category, _ := repository.Find(current.ID)
+
+	if category == nil {
+		return fmt.Errorf("not found")
+	}
+
+	if !same(category.ProductCount, current.ProductCount) || !same(category.TotalProductCount, current.TotalProductCount) {
+		return CountChangedError{ID: *current.ID}
+	}
+
+	category.ProductCount = &direct
+	category.TotalProductCount = &total
+
+	return nil
+}
+
+func same(stored, read *int64) bool {
+	return stored == nil && read == nil || stored != nil && read != nil && *stored == *read
+}
+
+func (repository *memoryRepository) Total() (int64, error) {
+	return int64(len(repository.items)), nil
+}
+
+func (repository *memoryRepository) AddProductCount(ID *string, direct, total int64) error {
+	category, _ := repository.Find(ID)
+
+	if category == nil {
+		return fmt.Errorf("not found")
+	}
+
+	category.ProductCount = add(category.ProductCount, direct)
+	category.TotalProductCount = add(category.TotalProductCount, total)
+
+	return nil
+}
+
+func add(current *int64, delta int64) *int64 {
+	result := delta
+
+	if current != nil {
+		result += *current
+	}
+
+	return &result
+}
+
+func (repository *memoryRepository) SetMultimedia(ID *string, items multimedia.Collection) error {
+	category, _ := repository.Find(ID)
+
+	if category == nil {
+		return fmt.Errorf("not found")
+	}
+
+	category.Multimedia = items
+
+	return nil
+}
+
+type memoryProducts struct {
+	assignments map[string]string
+}
+
+func (products *memoryProducts) ProductIDs(categoryID *string) ([]*string, error) {
+	ids := make([]*string, 0)
+
+	for product, category := range products.assignments {
+		if category == *categoryID {
+			ids = append(ids, s(product))
+		}
+	}
+
+	return ids, nil
+}
+
+func (products *memoryProducts) MoveProducts(productIDs []*string, categoryID *string) error {
+	for _, id := range productIDs {
+		products.assignments[*id] = *categoryID
+	}
+
+	return nil
+}
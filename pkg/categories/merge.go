@@ -0,0 +1,331 @@
+package categories
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/alejo-lapix/multimedia-go/banners"
+	"github.com/alejo-lapix/multimedia-go/persistence"
+)
+
+// BannerChoice tells the merge which banner the resulting category keeps
+type BannerChoice string
+
+const (
+	// PreferTargetBanner keeps the banner of the target and only
+	// uses the one of the source when the target does not have one
+	PreferTargetBanner BannerChoice = ""
+	KeepTargetBanner   BannerChoice = "target"
+	KeepSourceBanner   BannerChoice = "source"
+)
+
+const defaultMergeBatchSize = 25
+
+type MergeOptions struct {
+	Banner BannerChoice
+	// BatchSize is the amount of products moved on every step
+	BatchSize int
+	// Progress is called after every batch of products or subcategories
+	Progress func(progress *MergeProgress)
+}
+
+type MergeProgress struct {
+	Step  string `json:"step"`
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+}
+
+type MergeReport struct {
+	MovedProducts      []*string `json:"movedProducts"`
+	MovedSubCategories []*string `json:"movedSubCategories"`
+	AddedMultimedia    int       `json:"addedMultimedia"`
+	AddedBanners       int       `json:"addedBanners"`
+}
+
+// MergeCategoryService merges duplicated categories, every step reads the
+// current state again so an interrupted merge is resumed running it again
+type MergeCategoryService struct {
+	Repository CategoryRepository
+	Products   CategoryProducts
+}
+
+func NewMergeCategoryService(repository CategoryRepository, products CategoryProducts) *MergeCategoryService {
+	return &MergeCategoryService{
+		Repository: repository,
+		Products:   products,
+	}
+}
+
+// MergeCategories moves the products, subcategories, multimedia and scheduled banners
+// of the source category into the target and removes the source, it fails with
+// DuplicateNameError before writing anything when a subcategory of the source has
+// the name of a subcategory of the target
+func (service *MergeCategoryService) MergeCategories(sourceID, targetID *string, options *MergeOptions) (*MergeReport, error) {
+	if options == nil {
+		options = &MergeOptions{}
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultMergeBatchSize
+	}
+
+	source, target, err := service.categories(sourceID, targetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = service.checkNames(source, target); err != nil {
+		return nil, err
+	}
+
+	report := &MergeReport{MovedProducts: []*string{}, MovedSubCategories: []*string{}}
+	report.AddedMultimedia = mergeMultimedia(target, source)
+	report.AddedBanners = mergeBanners(target, source)
+	target.Banner = chooseBanner(target, source, options.Banner)
+
+	if err = service.Repository.Update(target.ID, target); err != nil {
+		return nil, err
+	}
+
+	if err = service.moveProducts(source, target, options, report); err != nil {
+		return nil, err
+	}
+
+	if err = service.moveSubCategories(source, target, options, report); err != nil {
+		return nil, err
+	}
+
+	if err = service.Repository.Remove(source.ID); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (service *MergeCategoryService) categories(sourceID, targetID *string) (*Category, *Category, error) {
+	if *sourceID == *targetID {
+		return nil, nil, fmt.Errorf("a category can not be merged into itself")
+	}
+
+	source, err := service.Repository.Find(sourceID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if source == nil {
+		return nil, nil, NotFoundError{ID: *sourceID}
+	}
+
+	target, err := service.Repository.Find(targetID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if target == nil {
+		return nil, nil, NotFoundError{ID: *targetID}
+	}
+
+	ancestors, err := Ancestors(service.Repository, targetID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, ancestor := range ancestors {
+		if *ancestor.ID == *sourceID {
+			return nil, nil, fmt.Errorf("the category \"%s\" can not be merged into one of its descendants", *sourceID)
+		}
+	}
+
+	return source, target, nil
+}
+
+// checkNames keeps the names of the siblings unique once the
+// subcategories of the source are moved into the target
+func (service *MergeCategoryService) checkNames(source, target *Category) error {
+	children, err := service.Repository.SubCategories(AdminAudience, source.ID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if child.Name == nil {
+			continue
+		}
+
+		sibling, err := service.Repository.FindByName(target.ID, child.Name)
+
+		if err != nil {
+			return err
+		}
+
+		if sibling != nil && *sibling.ID != *child.ID {
+			return NewDuplicateNameError(target.ID, child.Name)
+		}
+	}
+
+	return nil
+}
+
+func (service *MergeCategoryService) moveProducts(source, target *Category, options *MergeOptions, report *MergeReport) error {
+	pending, err := service.Products.ProductIDs(source.ID)
+
+	if err != nil {
+		return err
+	}
+
+	total := len(pending)
+
+	for len(pending) > 0 {
+		batch := pending
+
+		if len(batch) > options.BatchSize {
+			batch = batch[:options.BatchSize]
+		}
+
+		if err = service.Products.MoveProducts(batch, target.ID); err != nil {
+			return err
+		}
+
+		report.MovedProducts = append(report.MovedProducts, batch...)
+		notify(options, "products", len(report.MovedProducts), total)
+
+		previous := len(pending)
+
+		if pending, err = service.Products.ProductIDs(source.ID); err != nil {
+			return err
+		}
+
+		if len(pending) >= previous {
+			return fmt.Errorf("the products of the category \"%s\" could not be moved", *source.ID)
+		}
+	}
+
+	return nil
+}
+
+// moveSubCategories also moves the recursive product counter of every subcategory
+// from the chain of the source to the chain of the target, the counter is moved
+// before the subcategory and moved back when the subcategory can not be updated,
+// only a crash between both writes leaves it drifting, ProductCountService.Recompute
+// repairs it
+func (service *MergeCategoryService) moveSubCategories(source, target *Category, options *MergeOptions, report *MergeReport) error {
+	children, err := service.Repository.SubCategories(AdminAudience, source.ID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		var total int64
+
+		if child.TotalProductCount != nil {
+			total = *child.TotalProductCount
+		}
+
+		if total != 0 {
+			if err = moveTotal(service.Repository, source.ID, target.ID, total); err != nil {
+				return err
+			}
+		}
+
+		child.ParentCategoryID = target.ID
+		child.IsMainCategory = isMainCategory(target.ID)
+		// The position belongs to the previous siblings
+		child.Position = nil
+
+		if err = service.Repository.Update(child.ID, child); err != nil {
+			if total != 0 {
+				if undoErr := moveTotal(service.Repository, target.ID, source.ID, total); undoErr != nil {
+					return undoErr
+				}
+			}
+
+			return err
+		}
+
+		report.MovedSubCategories = append(report.MovedSubCategories, child.ID)
+		notify(options, "subcategories", len(report.MovedSubCategories), len(children))
+	}
+
+	return nil
+}
+
+func notify(options *MergeOptions, step string, done, total int) {
+	if options.Progress != nil {
+		options.Progress(&MergeProgress{Step: step, Done: done, Total: total})
+	}
+}
+
+// mergeMultimedia appends the items of the source that the
+// target does not have yet and returns how many were added
+func mergeMultimedia(target, source *Category) int {
+	existing := make(map[string]bool, len(target.Multimedia))
+
+	for _, item := range target.Multimedia {
+		if item != nil {
+			existing[*item.ID] = true
+		}
+	}
+
+	added := make([]*persistence.MultimediaItem, 0)
+
+	for _, item := range source.Multimedia {
+		if item == nil || existing[*item.ID] {
+			continue
+		}
+
+		existing[*item.ID] = true
+		added = append(added, item)
+	}
+
+	target.Multimedia = append(target.Multimedia, added...)
+
+	return len(added)
+}
+
+// mergeBanners appends the scheduled banners of the source that the
+// target does not have yet and returns how many were added
+func mergeBanners(target, source *Category) int {
+	added := 0
+
+	for _, scheduled := range source.Banners {
+		if scheduled == nil || scheduled.Banner == nil || hasBanner(target, scheduled) {
+			continue
+		}
+
+		target.Banners = append(target.Banners, scheduled)
+		added++
+	}
+
+	return added
+}
+
+func hasBanner(category *Category, scheduled *ScheduledBanner) bool {
+	for _, current := range category.Banners {
+		if reflect.DeepEqual(current, scheduled) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func chooseBanner(target, source *Category, choice BannerChoice) *banners.Banner {
+	switch choice {
+	case KeepSourceBanner:
+		return source.Banner
+	case KeepTargetBanner:
+		return target.Banner
+	}
+
+	if target.Banner != nil {
+		return target.Banner
+	}
+
+	return source.Banner
+}
@@ -0,0 +1,101 @@
+package categories
+
+import (
+	"testing"
+
+	"github.com/alejo-lapix/multimedia-go/banners"
+	"github.com/alejo-lapix/multimedia-go/persistence"
+)
+
+func TestMergeCategoryService_MergeCategories(t *testing.T) {
+	items := tree()
+	items = append(items, &Category{ID: s("drills-copy"), Name: s("Drills"), ParentCategoryID: s("tools"), Multimedia: []*persistence.MultimediaItem{{ID: s("a")}, {ID: s("b")}}})
+	items[2].Multimedia = []*persistence.MultimediaItem{{ID: s("a")}}
+	position := 1
+	items = append(items, &Category{ID: s("cordless"), ParentCategoryID: s("drills-copy"), Position: &position})
+	repository := newMemoryRepository(items...)
+	products := &memoryProducts{assignments: map[string]string{"p1": "drills-copy", "p2": "drills-copy", "p3": "drills-copy"}}
+	service := NewMergeCategoryService(repository, products)
+	steps := 0
+
+	report, err := service.MergeCategories(s("drills-copy"), s("drills"), &MergeOptions{
+		BatchSize: 2,
+		Progress:  func(progress *MergeProgress) { steps++ },
+	})
+
+	if err != nil {
+		t.Fatalf("MergeCategories() error = %v", err)
+	}
+
+	if len(report.MovedProducts) != 3 || len(report.MovedSubCategories) != 1 || report.AddedMultimedia != 1 {
+		t.Errorf("MergeCategories() report = %+v", report)
+	}
+
+	if steps != 3 {
+		t.Errorf("MergeCategories() progress steps = %d, want 3", steps)
+	}
+
+	if source, _ := repository.Find(s("drills-copy")); source != nil {
+		t.Errorf("MergeCategories() the source category was not removed")
+	}
+
+	cordless, _ := repository.Find(s("cordless"))
+
+	if *cordless.ParentCategoryID != "drills" {
+		t.Errorf("MergeCategories() parent = %s, want drills", *cordless.ParentCategoryID)
+	}
+
+	if cordless.Position != nil {
+		t.Errorf("MergeCategories() position = %d, want nil", *cordless.Position)
+	}
+
+	for product, category := range products.assignments {
+		if category != "drills" {
+			t.Errorf("MergeCategories() product %s in %s, want drills", product, category)
+		}
+	}
+}
+
+func TestMergeCategoryService_MergeIntoDescendant(t *testing.T) {
+	service := NewMergeCategoryService(newMemoryRepository(tree()...), &memoryProducts{assignments: map[string]string{}})
+
+	if _, err := service.MergeCategories(s("tools"), s("drills"), nil); err == nil {
+		t.Errorf("MergeCategories() expected an error merging into a descendant")
+	}
+}
+
+func TestMergeCategoryService_MergeDuplicatedSubCategoryNames(t *testing.T) {
+	items := tree()
+	items = append(items, &Category{ID: s("power-copy"), Name: s("Power"), ParentCategoryID: s("other")})
+	items = append(items, &Category{ID: s("drills-copy"), Name: s("DRILLS"), ParentCategoryID: s("power-copy")})
+	repository := newMemoryRepository(items...)
+	products := &memoryProducts{assignments: map[string]string{"p1": "power-copy"}}
+	service := NewMergeCategoryService(repository, products)
+
+	if _, err := service.MergeCategories(s("power-copy"), s("power"), nil); err == nil {
+		t.Fatalf("MergeCategories() expected a DuplicateNameError")
+	} else if _, ok := err.(DuplicateNameError); !ok {
+		t.Fatalf("MergeCategories() error = %v, want a DuplicateNameError", err)
+	}
+
+	if products.assignments["p1"] != "power-copy" {
+		t.Errorf("MergeCategories() moved the products before failing")
+	}
+}
+
+func TestMergeCategoryService_MergeBanners(t *testing.T) {
+	items := tree()
+	items[3].Banners = []*ScheduledBanner{{Banner: &banners.Banner{HtmlContent: s("sale")}}}
+	repository := newMemoryRepository(items...)
+	service := NewMergeCategoryService(repository, &memoryProducts{assignments: map[string]string{}})
+
+	report, err := service.MergeCategories(s("other"), s("tools"), nil)
+
+	if err != nil || report.AddedBanners != 1 {
+		t.Fatalf("MergeCategories() report = %+v, error = %v", report, err)
+	}
+
+	if target, _ := repository.Find(s("tools")); len(target.Banners) != 1 || *target.Banners[0].Banner.HtmlContent != "sale" {
+		t.Errorf("MergeCategories() banners = %v, want the scheduled banner of the source", target.Banners)
+	}
+}
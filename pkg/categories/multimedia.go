@@ -0,0 +1,41 @@
+package categories
+
+import "github.com/alejo-lapix/products-go/pkg/multimedia"
+
+const MultimediaOwner = "category"
+
+// MultimediaItems returns every multimedia item used by
+// the category, including the ones of its banners
+func (category *Category) MultimediaItems() multimedia.Collection {
+	items := make(multimedia.Collection, 0, len(category.Multimedia))
+	items = append(items, category.Multimedia...)
+
+	if category.Banner != nil && category.Banner.Multimedia != nil {
+		items = append(items, category.Banner.Multimedia)
+	}
+
+	for _, scheduled := range category.Banners {
+		if scheduled != nil && scheduled.Banner != nil && scheduled.Banner.Multimedia != nil {
+			items = append(items, scheduled.Banner.Multimedia)
+		}
+	}
+
+	return items
+}
+
+// MultimediaReferences lists the multimedia used by all the categories
+func MultimediaReferences(repository CategoryRepository) ([]*multimedia.Reference, error) {
+	items, err := repository.All()
+
+	if err != nil {
+		return nil, err
+	}
+
+	references := make([]*multimedia.Reference, 0)
+
+	for _, item := range items {
+		references = append(references, multimedia.NewReferences(MultimediaOwner, item.ID, item.MultimediaItems())...)
+	}
+
+	return references, nil
+}
@@ -0,0 +1,88 @@
+package categories
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var accents = map[rune]rune{
+	'á': 'a', 'à': 'a', 'ä': 'a', 'â': 'a', 'ã': 'a', 'å': 'a',
+	'é': 'e', 'è': 'e', 'ë': 'e', 'ê': 'e',
+	'í': 'i', 'ì': 'i', 'ï': 'i', 'î': 'i',
+	'ó': 'o', 'ò': 'o', 'ö': 'o', 'ô': 'o', 'õ': 'o',
+	'ú': 'u', 'ù': 'u', 'ü': 'u', 'û': 'u',
+	'ñ': 'n', 'ç': 'c', 'ý': 'y', 'ÿ': 'y',
+}
+
+// NormalizeName returns the form of the name used to compare categories,
+// it ignores the case, the accents and the repeated white spaces
+func NormalizeName(name string) string {
+	var builder strings.Builder
+
+	for _, word := range strings.Fields(name) {
+		if builder.Len() > 0 {
+			builder.WriteRune(' ')
+		}
+
+		for _, character := range word {
+			character = unicode.ToLower(character)
+
+			if replacement, ok := accents[character]; ok {
+				character = replacement
+			}
+
+			builder.WriteRune(character)
+		}
+	}
+
+	return builder.String()
+}
+
+// NameKey identifies a name among the siblings of the given parent
+func NameKey(parentCategoryID *string, name string) string {
+	parent := "root"
+
+	if parentCategoryID != nil && *parentCategoryID != "" {
+		parent = *parentCategoryID
+	}
+
+	return fmt.Sprintf("%s#%s", parent, NormalizeName(name))
+}
+
+// NameKey identifies the name of the category among its siblings
+func (category *Category) NameKey() string {
+	if category.Name == nil {
+		return NameKey(category.ParentCategoryID, "")
+	}
+
+	return NameKey(category.ParentCategoryID, *category.Name)
+}
+
+// DuplicateNameError is returned when a sibling category already has the name
+type DuplicateNameError struct {
+	ParentCategoryID string
+	Name             string
+}
+
+func (err DuplicateNameError) Error() string {
+	if err.ParentCategoryID == "" {
+		return fmt.Sprintf("a main category named \"%s\" already exists", err.Name)
+	}
+
+	return fmt.Sprintf("the category \"%s\" already has a subcategory named \"%s\"", err.ParentCategoryID, err.Name)
+}
+
+func NewDuplicateNameError(parentCategoryID *string, name *string) DuplicateNameError {
+	err := DuplicateNameError{}
+
+	if parentCategoryID != nil {
+		err.ParentCategoryID = *parentCategoryID
+	}
+
+	if name != nil {
+		err.Name = *name
+	}
+
+	return err
+}
@@ -0,0 +1,33 @@
+package categories
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Ignores the case", input: "Drills", want: "drills"},
+		{name: "Ignores the accents", input: "Electrónica Básica", want: "electronica basica"},
+		{name: "Collapses the white spaces", input: "  Power   Tools ", want: "power tools"},
+		{name: "Keeps other characters", input: "Niños & Bebés", want: "ninos & bebes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeName(tt.input); got != tt.want {
+				t.Errorf("NormalizeName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameKey(t *testing.T) {
+	if NameKey(nil, "Tools") != NameKey(s(""), "TOOLS") {
+		t.Errorf("NameKey() main categories must share the same parent key")
+	}
+
+	if NameKey(s("a"), "Tools") == NameKey(s("b"), "Tools") {
+		t.Errorf("NameKey() must depend on the parent")
+	}
+}
@@ -0,0 +1,135 @@
+package categories
+
+import (
+	"sort"
+
+	"github.com/alejo-lapix/products-go/pkg/ordering"
+)
+
+// SortCategories sorts the list by position, the categories
+// without a position go last sorted by name
+func SortCategories(items []*Category) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return ordering.Less(sortKey(items[i]), sortKey(items[j]))
+	})
+}
+
+func sortKey(category *Category) ordering.Key {
+	return ordering.Key{Position: category.Position, Name: category.Name, ID: category.ID}
+}
+
+type OrderCategoryService struct {
+	Repository CategoryRepository
+}
+
+func NewOrderCategoryService(repository CategoryRepository) *OrderCategoryService {
+	return &OrderCategoryService{Repository: repository}
+}
+
+// MoveBefore places the category right before its sibling anchorID
+func (service *OrderCategoryService) MoveBefore(ID, anchorID *string) error {
+	return service.move(ID, anchorID, false)
+}
+
+// MoveAfter places the category right after its sibling anchorID
+func (service *OrderCategoryService) MoveAfter(ID, anchorID *string) error {
+	return service.move(ID, anchorID, true)
+}
+
+// SetOrder replaces the order of the subcategories of parentID, or the
+// main categories when it is empty, the list must contain all of them
+func (service *OrderCategoryService) SetOrder(parentID *string, ids []*string) error {
+	siblings, err := service.siblings(parentID)
+
+	if err != nil {
+		return err
+	}
+
+	if err = ordering.Validate(categoryIDs(siblings), ids); err != nil {
+		return err
+	}
+
+	return service.persist(siblings, ids)
+}
+
+func (service *OrderCategoryService) move(ID, anchorID *string, after bool) error {
+	category, err := service.Repository.Find(ID)
+
+	if err != nil {
+		return err
+	}
+
+	if category == nil {
+		return NotFoundError{ID: *ID}
+	}
+
+	siblings, err := service.siblings(category.ParentCategoryID)
+
+	if err != nil {
+		return err
+	}
+
+	ids, err := ordering.Move(categoryIDs(siblings), ID, anchorID, after)
+
+	if err != nil {
+		return err
+	}
+
+	return service.persist(siblings, ids)
+}
+
+func (service *OrderCategoryService) siblings(parentID *string) ([]*Category, error) {
+	var siblings []*Category
+	var err error
+
+	if parentID == nil || *parentID == "" {
+		siblings, err = service.Repository.MainCategories(AdminAudience, 0, 0)
+	} else {
+		siblings, err = service.Repository.SubCategories(AdminAudience, parentID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	SortCategories(siblings)
+
+	return siblings, nil
+}
+
+// persist stores the new positions, only the categories
+// whose position actually changed are updated
+func (service *OrderCategoryService) persist(siblings []*Category, ids []*string) error {
+	byID := make(map[string]*Category, len(siblings))
+
+	for _, sibling := range siblings {
+		byID[*sibling.ID] = sibling
+	}
+
+	for index, id := range ids {
+		category := byID[*id]
+		position := index + 1
+
+		if category.Position != nil && *category.Position == position {
+			continue
+		}
+
+		category.Position = &position
+
+		if err := service.Repository.Update(category.ID, category); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func categoryIDs(items []*Category) []*string {
+	ids := make([]*string, len(items))
+
+	for index, item := range items {
+		ids[index] = item.ID
+	}
+
+	return ids
+}
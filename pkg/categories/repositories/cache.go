@@ -3,6 +3,7 @@ package repositories
 import (
 	"fmt"
 	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
 	"time"
 )
 
@@ -89,10 +90,10 @@ func (driver *inMemory) Has(key string) bool {
 	return ok
 }
 
-func (repository *CacheCategoryRepository) MainCategories(limit, offset int) ([]*categories.Category, error) {
-	signature := fmt.Sprintf("MainCategories %d-%d", limit, offset)
+func (repository *CacheCategoryRepository) MainCategories(audience categories.Audience, limit, offset int) ([]*categories.Category, error) {
+	signature := fmt.Sprintf("MainCategories %s %d-%d", audience, limit, offset)
 	elements, err := repository.cache.Remember(signature, repository.ttl, func() (interface{}, error) {
-		return repository.CategoryRepository.MainCategories(limit, offset)
+		return repository.CategoryRepository.MainCategories(audience, limit, offset)
 	})
 
 	if err != nil {
@@ -102,10 +103,10 @@ func (repository *CacheCategoryRepository) MainCategories(limit, offset int) ([]
 	return elements.([]*categories.Category), nil
 }
 
-func (repository *CacheCategoryRepository) SubCategories(categoryID *string) ([]*categories.Category, error) {
-	signature := fmt.Sprintf("SubCategories %s", *categoryID)
+func (repository *CacheCategoryRepository) SubCategories(audience categories.Audience, categoryID *string) ([]*categories.Category, error) {
+	signature := fmt.Sprintf("SubCategories %s %s", audience, *categoryID)
 	elements, err := repository.cache.Remember(signature, repository.ttl, func() (interface{}, error) {
-		return repository.CategoryRepository.SubCategories(categoryID)
+		return repository.CategoryRepository.SubCategories(audience, categoryID)
 	})
 
 	if err != nil {
@@ -132,6 +133,10 @@ func (repository *CacheCategoryRepository) FindMany(ids []*string) ([]*categorie
 	return repository.CategoryRepository.FindMany(ids)
 }
 
+func (repository *CacheCategoryRepository) FindByName(parentCategoryID, name *string) (*categories.Category, error) {
+	return repository.CategoryRepository.FindByName(parentCategoryID, name)
+}
+
 func (repository *CacheCategoryRepository) Store(category *categories.Category) error {
 	return repository.CategoryRepository.Store(category)
 }
@@ -140,6 +145,22 @@ func (repository *CacheCategoryRepository) Remove(ID *string) error {
 	return repository.CategoryRepository.Remove(ID)
 }
 
+func (repository *CacheCategoryRepository) Restore(ID *string) error {
+	return repository.CategoryRepository.Restore(ID)
+}
+
+func (repository *CacheCategoryRepository) Trash() ([]*categories.Category, error) {
+	return repository.CategoryRepository.Trash()
+}
+
+func (repository *CacheCategoryRepository) FindInTrash(ID *string) (*categories.Category, error) {
+	return repository.CategoryRepository.FindInTrash(ID)
+}
+
+func (repository *CacheCategoryRepository) Purge(before time.Time) ([]*categories.Category, error) {
+	return repository.CategoryRepository.Purge(before)
+}
+
 func (repository *CacheCategoryRepository) Update(ID *string, category *categories.Category) error {
 	return repository.CategoryRepository.Update(ID, category)
 }
@@ -159,3 +180,15 @@ func (repository *CacheCategoryRepository) All() ([]*categories.Category, error)
 func (repository *CacheCategoryRepository) Total() (int64, error) {
 	return repository.CategoryRepository.Total()
 }
+
+func (repository *CacheCategoryRepository) SetMultimedia(ID *string, items multimedia.Collection) error {
+	return repository.CategoryRepository.SetMultimedia(ID, items)
+}
+
+func (repository *CacheCategoryRepository) AddProductCount(ID *string, direct, total int64) error {
+	return repository.CategoryRepository.AddProductCount(ID, direct, total)
+}
+
+func (repository *CacheCategoryRepository) SetProductCount(current *categories.Category, direct, total int64) error {
+	return repository.CategoryRepository.SetProductCount(current, direct, total)
+}
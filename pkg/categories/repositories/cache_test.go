@@ -34,11 +34,11 @@ func temporalElements() []*categories.Category {
 	}
 }
 
-func (repo repository) MainCategories(limit, offset int) ([]*categories.Category, error) {
+func (repo repository) MainCategories(audience categories.Audience, limit, offset int) ([]*categories.Category, error) {
 	return commonElements(), nil
 }
 
-func (repo repository) SubCategories(categoryID *string) ([]*categories.Category, error) {
+func (repo repository) SubCategories(audience categories.Audience, categoryID *string) ([]*categories.Category, error) {
 	return []*categories.Category{}, nil
 }
 func (repo repository) Find(ID *string) (*categories.Category, error) {
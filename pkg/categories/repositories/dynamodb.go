@@ -3,14 +3,25 @@ package repositories
 import (
 	"fmt"
 	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/dynamo"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"strconv"
+	"time"
 )
 
 type DynamoDBCategoryRepository struct {
-	DynamoDB            *dynamodb.DynamoDB
+	DynamoDB *dynamodb.DynamoDB
+	Clock    clock.Clock
+
+	// Retention lets the TTL of the table purge the removed
+	// categories, see ReleaseExpired before setting it
+	Retention           time.Duration
 	tableName           *string
+	namesTableName      *string
 	parentCategoryTries int
 }
 
@@ -18,23 +29,28 @@ func NewDynamoDBCategoryRepository(db *dynamodb.DynamoDB) *DynamoDBCategoryRepos
 	tableName := "categories"
 
 	return &DynamoDBCategoryRepository{
-		DynamoDB:  db,
-		tableName: &tableName,
+		DynamoDB:       db,
+		Clock:          clock.System,
+		tableName:      &tableName,
+		namesTableName: aws.String("category-names"),
 	}
 }
 
-func (repository *DynamoDBCategoryRepository) MainCategories(limit, offset int) ([]*categories.Category, error) {
+func (repository *DynamoDBCategoryRepository) MainCategories(audience categories.Audience, limit, offset int) ([]*categories.Category, error) {
 	items := make([]*categories.Category, 0)
-	output, err := repository.DynamoDB.Query(&dynamodb.QueryInput{
-		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":yes":     {S: aws.String("y")},
-			":visible": {BOOL: aws.Bool(true)},
-		},
-		FilterExpression:       aws.String("visible = :visible"),
-		IndexName:              aws.String("isMainCategory-index"),
-		KeyConditionExpression: aws.String("isMainCategory = :yes"),
-		TableName:              repository.tableName,
-	})
+	input := &dynamodb.QueryInput{
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":yes": {S: aws.String("y")}},
+		IndexName:                 aws.String("isMainCategory-index"),
+		KeyConditionExpression:    aws.String("isMainCategory = :yes"),
+		FilterExpression:          aws.String(dynamo.NotDeleted),
+		TableName:                 repository.tableName,
+	}
+
+	if audience != categories.AdminAudience {
+		onlyVisible(input)
+	}
+
+	output, err := repository.DynamoDB.Query(input)
 
 	if err != nil {
 		return nil, err
@@ -46,11 +62,20 @@ func (repository *DynamoDBCategoryRepository) MainCategories(limit, offset int)
 		return nil, err
 	}
 
+	categories.SortCategories(items)
+
 	return items, nil
 }
 
+// onlyVisible adds the visibility filter to the given query
+func onlyVisible(input *dynamodb.QueryInput) {
+	input.ExpressionAttributeValues[":visible"] = &dynamodb.AttributeValue{BOOL: aws.Bool(true)}
+	input.FilterExpression = aws.String(*input.FilterExpression + " AND visible = :visible")
+}
+
 func (repository *DynamoDBCategoryRepository) Total() (int64, error) {
 	output, err := repository.DynamoDB.Scan(&dynamodb.ScanInput{
+		FilterExpression:       aws.String(dynamo.NotDeleted),
 		ReturnConsumedCapacity: aws.String("TOTAL"),
 		Select:                 aws.String("COUNT"),
 		TableName:              repository.tableName,
@@ -63,14 +88,27 @@ func (repository *DynamoDBCategoryRepository) Total() (int64, error) {
 	return *output.Count, nil
 }
 
-func (repository *DynamoDBCategoryRepository) SubCategories(categoryID *string) ([]*categories.Category, error) {
+func (repository *DynamoDBCategoryRepository) SubCategories(audience categories.Audience, categoryID *string) ([]*categories.Category, error) {
 	mainCategories := make([]*categories.Category, 0)
-	output, err := repository.DynamoDB.Query(&dynamodb.QueryInput{
+	input := &dynamodb.QueryInput{
 		KeyConditionExpression:    aws.String("parentCategoryId = :categoryId"),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":categoryId": {S: categoryID}},
+		FilterExpression:          aws.String(dynamo.NotDeleted),
 		IndexName:                 aws.String("parentCategoryId-index"),
 		TableName:                 repository.tableName,
-	})
+	}
+
+	if audience != categories.AdminAudience {
+		visible, err := categories.IsEffectivelyVisible(repository, categoryID)
+
+		if err != nil || !visible {
+			return mainCategories, err
+		}
+
+		onlyVisible(input)
+	}
+
+	output, err := repository.DynamoDB.Query(input)
 
 	if err != nil {
 		return nil, err
@@ -82,25 +120,24 @@ func (repository *DynamoDBCategoryRepository) SubCategories(categoryID *string)
 		return nil, err
 	}
 
+	categories.SortCategories(mainCategories)
+
 	return mainCategories, nil
 }
 
+// All reads every page of the base table, the indexes do not project every attribute
 func (repository *DynamoDBCategoryRepository) All() ([]*categories.Category, error) {
-	currentCategory := make([]*categories.Category, 0)
-	scanInput := &dynamodb.ScanInput{TableName: repository.tableName, IndexName: aws.String("id-name-index")}
-	output, err := repository.DynamoDB.Scan(scanInput)
-
-	if err != nil {
-		return nil, err
+	items := make([]*categories.Category, 0)
+	input := &dynamodb.ScanInput{
+		FilterExpression: aws.String(dynamo.NotDeleted),
+		TableName:        repository.tableName,
 	}
 
-	err = dynamodbattribute.UnmarshalListOfMaps(output.Items, &currentCategory)
-
-	if err != nil {
+	if err := dynamo.Scan(repository.DynamoDB, input, &items); err != nil {
 		return nil, err
 	}
 
-	return currentCategory, nil
+	return items, nil
 }
 
 func (repository *DynamoDBCategoryRepository) FindMainCategory(childCategoryID *string) (*categories.Category, error) {
@@ -134,6 +171,17 @@ func (repository *DynamoDBCategoryRepository) resetRetries() {
 }
 
 func (repository *DynamoDBCategoryRepository) Find(ID *string) (*categories.Category, error) {
+	category, err := repository.find(ID)
+
+	if err != nil || category == nil || category.DeletedAt != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// find also returns the removed categories
+func (repository *DynamoDBCategoryRepository) find(ID *string) (*categories.Category, error) {
 	currentCategory := &categories.Category{}
 	output, err := repository.DynamoDB.GetItem(&dynamodb.GetItemInput{
 		Key:       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
@@ -144,6 +192,10 @@ func (repository *DynamoDBCategoryRepository) Find(ID *string) (*categories.Cate
 		return nil, err
 	}
 
+	if output.Item == nil {
+		return nil, nil
+	}
+
 	err = dynamodbattribute.UnmarshalMap(output.Item, currentCategory)
 
 	if err != nil {
@@ -154,68 +206,153 @@ func (repository *DynamoDBCategoryRepository) Find(ID *string) (*categories.Cate
 }
 
 func (repository *DynamoDBCategoryRepository) FindMany(items []*string) ([]*categories.Category, error) {
-	list := make([]*categories.Category, len(items))
+	list := make([]*categories.Category, 0, len(items))
 	keys := make([]map[string]*dynamodb.AttributeValue, len(items))
 
 	for index, item := range items {
 		keys[index] = map[string]*dynamodb.AttributeValue{"id": {S: item}}
 	}
 
-	output, err := repository.DynamoDB.BatchGetItem(&dynamodb.BatchGetItemInput{
-		RequestItems:           map[string]*dynamodb.KeysAndAttributes{*repository.tableName: {Keys: keys}},
-		ReturnConsumedCapacity: nil,
-	})
-
-	if err != nil {
+	if err := dynamo.BatchGet(repository.DynamoDB, repository.tableName, keys, &list); err != nil {
 		return nil, err
 	}
 
-	err = dynamodbattribute.UnmarshalListOfMaps(output.Responses[*repository.tableName], &list)
+	return withoutDeleted(list), nil
+}
+
+func (repository *DynamoDBCategoryRepository) Store(category *categories.Category) error {
+	item, err := dynamodbattribute.MarshalMap(category)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return list, nil
+	_, err = repository.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			repository.reserveName(category),
+			{Put: &dynamodb.Put{
+				ConditionExpression: aws.String("attribute_not_exists(id)"),
+				Item:                item,
+				TableName:           repository.tableName,
+			}},
+		},
+	})
+
+	return nameError(err, category)
 }
 
-func (repository *DynamoDBCategoryRepository) Store(category *categories.Category) error {
+// Update only touches the names table when the
+// name or the parent of the category changed
+func (repository *DynamoDBCategoryRepository) Update(ID *string, category *categories.Category) error {
 	item, err := dynamodbattribute.MarshalMap(category)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
-		ConditionExpression: aws.String("attribute_not_exists(id)"),
-		Item:                item,
-		TableName:           repository.tableName,
+	current, err := repository.Find(ID)
+
+	if err != nil {
+		return err
+	}
+
+	put := &dynamodb.Put{
+		ConditionExpression:       aws.String("id = :id AND " + dynamo.NotDeleted),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":id": {S: ID}},
+		Item:                      item,
+		TableName:                 repository.tableName,
+	}
+
+	if current == nil || current.NameKey() == category.NameKey() {
+		_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+			ConditionExpression:       put.ConditionExpression,
+			ExpressionAttributeValues: put.ExpressionAttributeValues,
+			Item:                      put.Item,
+			TableName:                 put.TableName,
+		})
+
+		return err
+	}
+
+	_, err = repository.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			repository.reserveName(category),
+			repository.releaseName(current),
+			{Put: put},
+		},
+	})
+
+	return nameError(err, category)
+}
+
+func (repository *DynamoDBCategoryRepository) AddProductCount(ID *string, direct, total int64) error {
+	_, err := repository.DynamoDB.UpdateItem(&dynamodb.UpdateItemInput{
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":direct": {N: aws.String(strconv.FormatInt(direct, 10))},
+			":total":  {N: aws.String(strconv.FormatInt(total, 10))},
+		},
+		Key:              map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+		TableName:        repository.tableName,
+		UpdateExpression: aws.String("ADD productCount :direct, totalProductCount :total"),
 	})
 
 	return err
 }
 
-func (repository *DynamoDBCategoryRepository) Remove(ID *string) error {
-	_, err := repository.DynamoDB.DeleteItem(&dynamodb.DeleteItemInput{
-		Key:       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
-		TableName: repository.tableName,
+// SetProductCount writes the counters with a SET conditioned on the values read
+// in the category, so the concurrent ADD of AddProductCount are never overwritten
+func (repository *DynamoDBCategoryRepository) SetProductCount(current *categories.Category, direct, total int64) error {
+	values := map[string]*dynamodb.AttributeValue{
+		":direct": {N: aws.String(strconv.FormatInt(direct, 10))},
+		":total":  {N: aws.String(strconv.FormatInt(total, 10))},
+	}
+	condition := "attribute_exists(id) AND " + counterCondition("productCount", ":previousDirect", current.ProductCount, values) +
+		" AND " + counterCondition("totalProductCount", ":previousTotal", current.TotalProductCount, values)
+	_, err := repository.DynamoDB.UpdateItem(&dynamodb.UpdateItemInput{
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeValues: values,
+		Key:                       map[string]*dynamodb.AttributeValue{"id": {S: current.ID}},
+		TableName:                 repository.tableName,
+		UpdateExpression:          aws.String("SET productCount = :direct, totalProductCount = :total"),
 	})
 
+	if dynamo.ConditionFailed(err) {
+		return categories.CountChangedError{ID: *current.ID}
+	}
+
 	return err
 }
 
-func (repository *DynamoDBCategoryRepository) Update(ID *string, category *categories.Category) error {
-	item, err := dynamodbattribute.MarshalMap(category)
+// counterCondition expects the counter to be missing when it was not read
+func counterCondition(attribute, placeholder string, previous *int64, values map[string]*dynamodb.AttributeValue) string {
+	if previous == nil {
+		return "attribute_not_exists(" + attribute + ")"
+	}
+
+	values[placeholder] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(*previous, 10))}
+
+	return attribute + " = " + placeholder
+}
+
+// MultimediaOwners reads the multimedia of the categories and their banners, the removed ones included
+func (repository *DynamoDBCategoryRepository) MultimediaOwners() *multimedia.DynamoDBOwners {
+	return multimedia.NewDynamoDBOwners(repository.DynamoDB, *repository.tableName, "multimedia", "banner", "banners")
+}
+
+func (repository *DynamoDBCategoryRepository) SetMultimedia(ID *string, items multimedia.Collection) error {
+	value, err := dynamodbattribute.Marshal(items)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
-		ConditionExpression:       aws.String("id = :id"),
-		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":id": {S: ID}},
-		Item:                      item,
+	_, err = repository.DynamoDB.UpdateItem(&dynamodb.UpdateItemInput{
+		ConditionExpression:       aws.String("attribute_exists(id)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":multimedia": value},
+		Key:                       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
 		TableName:                 repository.tableName,
+		UpdateExpression:          aws.String("SET multimedia = :multimedia"),
 	})
 
 	return err
@@ -116,8 +116,9 @@ func TestDynamoDBCategoryRepository_MainCategories(t *testing.T) {
 		tableName *string
 	}
 	type args struct {
-		limit  int
-		offset int
+		audience categories.Audience
+		limit    int
+		offset   int
 	}
 	tests := []struct {
 		name    string
@@ -134,7 +135,7 @@ func TestDynamoDBCategoryRepository_MainCategories(t *testing.T) {
 				DynamoDB:  tt.fields.DynamoDB,
 				tableName: tt.fields.tableName,
 			}
-			got, err := repository.MainCategories(tt.args.limit, tt.args.offset)
+			got, err := repository.MainCategories(tt.args.audience, tt.args.limit, tt.args.offset)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("MainCategories() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -210,6 +211,7 @@ func TestDynamoDBCategoryRepository_SubCategories(t *testing.T) {
 		tableName *string
 	}
 	type args struct {
+		audience   categories.Audience
 		categoryID *string
 	}
 	tests := []struct {
@@ -227,7 +229,7 @@ func TestDynamoDBCategoryRepository_SubCategories(t *testing.T) {
 				DynamoDB:  tt.fields.DynamoDB,
 				tableName: tt.fields.tableName,
 			}
-			got, err := repository.SubCategories(tt.args.categoryID)
+			got, err := repository.SubCategories(tt.args.audience, tt.args.categoryID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SubCategories() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
+)
+
+type releaser interface {
+	Release(items multimedia.Collection) error
+}
+
+// MultimediaCategoryRepository releases the multimedia of the categories once they
+// are purged, the removed categories keep it in case they are restored
+type MultimediaCategoryRepository struct {
+	categories.CategoryRepository
+	releaser releaser
+}
+
+func NewMultimediaCategoryRepository(repository categories.CategoryRepository, releaser releaser) *MultimediaCategoryRepository {
+	return &MultimediaCategoryRepository{
+		CategoryRepository: repository,
+		releaser:           releaser,
+	}
+}
+
+// Purge releases the multimedia of every purged category in one call, so the owners are read once
+func (repository *MultimediaCategoryRepository) Purge(before time.Time) ([]*categories.Category, error) {
+	purged, err := repository.CategoryRepository.Purge(before)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(multimedia.Collection, 0)
+
+	for _, item := range purged {
+		items = append(items, item.MultimediaItems()...)
+	}
+
+	if len(items) == 0 {
+		return purged, nil
+	}
+
+	if err = repository.releaser.Release(items); err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
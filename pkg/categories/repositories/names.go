@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/dynamo"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// The names table keeps one item per category name and parent, it
+// is written in the same transaction of the category so two siblings
+// can never share a name, the key is categories.NameKey
+
+// reserveName must always be the first item of the transaction,
+// nameError relies on it to detect duplicated names
+func (repository *DynamoDBCategoryRepository) reserveName(category *categories.Category) *dynamodb.TransactWriteItem {
+	return &dynamodb.TransactWriteItem{Put: &dynamodb.Put{
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"id":         {S: aws.String(category.NameKey())},
+			"categoryId": {S: category.ID},
+		},
+		TableName: repository.namesTableName,
+	}}
+}
+
+// releaseName does not fail when the category was stored
+// before the names table existed and has no item there
+func (repository *DynamoDBCategoryRepository) releaseName(category *categories.Category) *dynamodb.TransactWriteItem {
+	return &dynamodb.TransactWriteItem{Delete: &dynamodb.Delete{
+		ConditionExpression:       aws.String("attribute_not_exists(id) OR categoryId = :categoryId"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":categoryId": {S: category.ID}},
+		Key:                       map[string]*dynamodb.AttributeValue{"id": {S: aws.String(category.NameKey())}},
+		TableName:                 repository.namesTableName,
+	}}
+}
+
+// nameError translates the cancellation of the transaction
+// caused by the names table into a DuplicateNameError
+func nameError(err error, category *categories.Category) error {
+	if dynamo.CancelledBy(err) == 0 {
+		return categories.NewDuplicateNameError(category.ParentCategoryID, category.Name)
+	}
+
+	return err
+}
+
+// BackfillNames reserves the names of the categories stored before the names table
+// existed, until it runs those names are not protected by Store and Update, it
+// returns the categories whose name is already taken by a sibling, they must be
+// renamed, and can run again after an interruption
+func (repository *DynamoDBCategoryRepository) BackfillNames() ([]*string, error) {
+	items := make([]*categories.Category, 0)
+	err := dynamo.Scan(repository.DynamoDB, &dynamodb.ScanInput{
+		FilterExpression: aws.String(dynamo.NotDeleted),
+		TableName:        repository.tableName,
+	}, &items)
+
+	if err != nil {
+		return nil, err
+	}
+
+	duplicated := make([]*string, 0)
+
+	for _, item := range items {
+		if item.Name == nil {
+			continue
+		}
+
+		reserve := repository.reserveName(item).Put
+		_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+			ConditionExpression:       aws.String("attribute_not_exists(id) OR categoryId = :categoryId"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":categoryId": {S: item.ID}},
+			Item:                      reserve.Item,
+			TableName:                 reserve.TableName,
+		})
+
+		if dynamo.ConditionFailed(err) {
+			duplicated = append(duplicated, item.ID)
+
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return duplicated, nil
+}
+
+func (repository *DynamoDBCategoryRepository) FindByName(parentCategoryID, name *string) (*categories.Category, error) {
+	output, err := repository.DynamoDB.GetItem(&dynamodb.GetItemInput{
+		Key:       map[string]*dynamodb.AttributeValue{"id": {S: aws.String(categories.NameKey(parentCategoryID, *name))}},
+		TableName: repository.namesTableName,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if categoryID, ok := output.Item["categoryId"]; ok {
+		return repository.Find(categoryID.S)
+	}
+
+	return repository.findSiblingByName(parentCategoryID, name)
+}
+
+// findSiblingByName covers the categories stored before the names table existed
+func (repository *DynamoDBCategoryRepository) findSiblingByName(parentCategoryID, name *string) (*categories.Category, error) {
+	var siblings []*categories.Category
+	var err error
+
+	if parentCategoryID == nil || *parentCategoryID == "" {
+		siblings, err = repository.MainCategories(categories.AdminAudience, 0, 0)
+	} else {
+		siblings, err = repository.SubCategories(categories.AdminAudience, parentCategoryID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	expected := categories.NormalizeName(*name)
+
+	for _, sibling := range siblings {
+		if sibling.Name != nil && categories.NormalizeName(*sibling.Name) == expected {
+			return sibling, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/dynamo"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+func withoutDeleted(items []*categories.Category) []*categories.Category {
+	result := make([]*categories.Category, 0, len(items))
+
+	for _, item := range items {
+		if item != nil && item.DeletedAt == nil {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+func (repository *DynamoDBCategoryRepository) now() time.Time {
+	if repository.Clock == nil {
+		return clock.System.Now()
+	}
+
+	return repository.Clock.Now()
+}
+
+// Remove releases the name in the same transaction that marks the category
+func (repository *DynamoDBCategoryRepository) Remove(ID *string) error {
+	category, err := repository.Find(ID)
+
+	if err != nil {
+		return err
+	}
+
+	if category == nil {
+		return nil
+	}
+
+	expression, values := dynamo.SoftDelete(repository.now(), repository.Retention)
+	_, err = repository.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			repository.releaseName(category),
+			{Update: &dynamodb.Update{
+				ConditionExpression:       aws.String("attribute_exists(id) AND " + dynamo.NotDeleted),
+				ExpressionAttributeValues: values,
+				Key:                       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+				TableName:                 repository.tableName,
+				UpdateExpression:          expression,
+			}},
+		},
+	})
+
+	return err
+}
+
+func (repository *DynamoDBCategoryRepository) Restore(ID *string) error {
+	category, err := repository.find(ID)
+
+	if err != nil {
+		return err
+	}
+
+	if category == nil {
+		return categories.NotFoundError{ID: *ID}
+	}
+
+	if category.DeletedAt == nil {
+		return nil
+	}
+
+	_, err = repository.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			repository.reserveName(category),
+			{Update: &dynamodb.Update{
+				ConditionExpression: aws.String("attribute_exists(deletedAt)"),
+				Key:                 map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+				TableName:           repository.tableName,
+				UpdateExpression:    aws.String(dynamo.Undelete),
+			}},
+		},
+	})
+
+	return nameError(err, category)
+}
+
+func (repository *DynamoDBCategoryRepository) Trash() ([]*categories.Category, error) {
+	items := make([]*categories.Category, 0)
+
+	if err := dynamo.Trash(repository.DynamoDB, repository.tableName, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (repository *DynamoDBCategoryRepository) FindInTrash(ID *string) (*categories.Category, error) {
+	category, err := repository.find(ID)
+
+	if err != nil || category == nil || category.DeletedAt == nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Purge skips the categories restored while it runs
+func (repository *DynamoDBCategoryRepository) Purge(before time.Time) ([]*categories.Category, error) {
+	trash, err := repository.Trash()
+
+	if err != nil {
+		return nil, err
+	}
+
+	purged := make([]*categories.Category, 0)
+
+	for _, item := range trash {
+		if !item.DeletedAt.Before(before) {
+			continue
+		}
+
+		deleted, err := dynamo.Purge(repository.DynamoDB, repository.tableName, map[string]*dynamodb.AttributeValue{"id": {S: item.ID}})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !deleted {
+			continue
+		}
+
+		purged = append(purged, item)
+	}
+
+	return purged, nil
+}
+
+// ReleaseExpired returns the categories removed by the TTL of the table, their names were
+// released by Remove but the TTL never runs Purge, so call it with the records of the
+// stream of the table when Retention is set and release the multimedia of the result
+func (repository *DynamoDBCategoryRepository) ReleaseExpired(records []*dynamodbstreams.Record) ([]*categories.Category, error) {
+	items := make([]*categories.Category, 0)
+
+	if err := dynamo.Expired(records, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -1,22 +1,55 @@
 package categories
 
 import (
+	"fmt"
+
 	"github.com/alejo-lapix/multimedia-go/banners"
 	"github.com/alejo-lapix/multimedia-go/persistence"
 )
 
-type StoreCategoryService struct {
-	repository CategoryRepository
+type CategoryService struct {
+	Repository CategoryRepository
+	Products   CategoryProducts
+	Factory    *Factory
 }
 
-func (service *StoreCategoryService) NewCategory(name, description, parentCategoryID *string, visible *bool, multimedia []*persistence.MultimediaItem, banner *banners.Banner) (*Category, error) {
-	category, err := NewCategory(name, description, parentCategoryID, visible, multimedia, banner)
+// Deprecated: use CategoryService
+type StoreCategoryService = CategoryService
+
+func NewCategoryService(repository CategoryRepository, products CategoryProducts) *CategoryService {
+	return &CategoryService{
+		Repository: repository,
+		Products:   products,
+		Factory:    DefaultFactory,
+	}
+}
+
+func (service *CategoryService) factory() *Factory {
+	if service.Factory == nil {
+		return DefaultFactory
+	}
+
+	return service.Factory
+}
+
+func (service *CategoryService) NewCategory(name, description, parentCategoryID *string, visible *bool, multimedia []*persistence.MultimediaItem, banner *banners.Banner) (*Category, error) {
+	category, err := service.factory().NewCategory(name, description, parentCategoryID, visible, multimedia, banner)
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = service.repository.Store(category)
+	if parentCategoryID != nil && *parentCategoryID != "" {
+		if _, err = service.FindCategory(parentCategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = service.checkName(category.ID, parentCategoryID, name); err != nil {
+		return nil, err
+	}
+
+	err = service.Repository.Store(category)
 
 	if err != nil {
 		return nil, err
@@ -24,3 +57,168 @@ func (service *StoreCategoryService) NewCategory(name, description, parentCatego
 
 	return category, nil
 }
+
+func (service *CategoryService) FindCategory(ID *string) (*Category, error) {
+	if err := service.factory().CheckID(ID); err != nil {
+		return nil, err
+	}
+
+	category, err := service.Repository.Find(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if category == nil {
+		return nil, NotFoundError{ID: *ID}
+	}
+
+	return category, nil
+}
+
+// UpdateCategory replaces the editable attributes of the category, the
+// parent and the product counters are kept, use Move to change the parent
+func (service *CategoryService) UpdateCategory(ID *string, category *Category) (*Category, error) {
+	current, err := service.FindCategory(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	category.ID = current.ID
+	category.CreatedAt = current.CreatedAt
+	category.ParentCategoryID = current.ParentCategoryID
+	category.IsMainCategory = current.IsMainCategory
+	category.ProductCount = current.ProductCount
+	category.TotalProductCount = current.TotalProductCount
+
+	return category, service.save(current, category)
+}
+
+func (service *CategoryService) Rename(ID, name *string) (*Category, error) {
+	category, err := service.FindCategory(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	current := *category
+	category.Name = name
+
+	return category, service.save(&current, category)
+}
+
+func (service *CategoryService) SetVisibility(ID *string, visible bool) (*Category, error) {
+	category, err := service.FindCategory(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	current := *category
+	category.Visible = &visible
+
+	return category, service.save(&current, category)
+}
+
+// Move changes the parent of the category, an empty parent turns it into
+// a main category, the recursive product counters follow the category
+func (service *CategoryService) Move(ID, parentCategoryID *string) (*Category, error) {
+	category, err := service.FindCategory(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	previousParentID := category.ParentCategoryID
+
+	if parentCategoryID != nil && *parentCategoryID == "" {
+		parentCategoryID = nil
+	}
+
+	if parentCategoryID != nil {
+		if err = service.checkParent(ID, parentCategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	current := *category
+	category.ParentCategoryID = parentCategoryID
+	category.IsMainCategory = isMainCategory(parentCategoryID)
+	// The position belongs to the previous siblings
+	category.Position = nil
+
+	if err = service.save(&current, category); err != nil {
+		return nil, err
+	}
+
+	if category.TotalProductCount == nil || *category.TotalProductCount == 0 {
+		return category, nil
+	}
+
+	if err = moveTotal(service.Repository, previousParentID, parentCategoryID, *category.TotalProductCount); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (service *CategoryService) Delete(ID *string, options *DeleteOptions) (*DeletionReport, error) {
+	return NewDeleteCategoryService(service.Repository, service.Products).Delete(ID, options)
+}
+
+// checkParent rejects parents that do not exist or that are
+// the category itself or one of its descendants
+func (service *CategoryService) checkParent(ID, parentCategoryID *string) error {
+	if *ID == *parentCategoryID {
+		return fmt.Errorf("a category can not be its own parent")
+	}
+
+	if _, err := service.FindCategory(parentCategoryID); err != nil {
+		return err
+	}
+
+	ancestors, err := Ancestors(service.Repository, parentCategoryID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, ancestor := range ancestors {
+		if *ancestor.ID == *ID {
+			return fmt.Errorf("the category \"%s\" can not be moved into one of its descendants", *ID)
+		}
+	}
+
+	return nil
+}
+
+// checkName gives a clear error before writing, the
+// repository still enforces the uniqueness on its own
+func (service *CategoryService) checkName(ID, parentCategoryID, name *string) error {
+	sibling, err := service.Repository.FindByName(parentCategoryID, name)
+
+	if err != nil {
+		return err
+	}
+
+	if sibling != nil && *sibling.ID != *ID {
+		return NewDuplicateNameError(parentCategoryID, name)
+	}
+
+	return nil
+}
+
+func (service *CategoryService) save(current, category *Category) error {
+	if err := Validate(category); err != nil {
+		return err
+	}
+
+	if current.NameKey() != category.NameKey() {
+		if err := service.checkName(category.ID, category.ParentCategoryID, category.Name); err != nil {
+			return err
+		}
+	}
+
+	return service.Repository.Update(category.ID, category)
+}
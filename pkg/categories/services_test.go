@@ -0,0 +1,88 @@
+package categories
+
+import (
+	"testing"
+
+	"github.com/alejo-lapix/products-go/pkg/ids"
+)
+
+func TestCategoryService_NewCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		category string
+		parentID *string
+		wantErr  bool
+	}{
+		{name: "Creates a subcategory", category: "Saws", parentID: s("power")},
+		{name: "Creates a main category", category: "Garden"},
+		{name: "Rejects unknown parents", category: "Saws", parentID: s("unknown"), wantErr: true},
+		{name: "Rejects repeated names among siblings", category: "  drílls ", parentID: s("power"), wantErr: true},
+		{name: "Rejects blank names", category: " ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCategoryService(newMemoryRepository(tree()...), &memoryProducts{})
+			service.Factory = testFactory()
+			_, err := service.NewCategory(s(tt.category), nil, tt.parentID, b(true), nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCategory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCategoryService_Move(t *testing.T) {
+	items := tree()
+	items[0].TotalProductCount = count(2)
+	items[1].TotalProductCount = count(2)
+	items[2].TotalProductCount = count(2)
+	repository := newMemoryRepository(items...)
+	service := NewCategoryService(repository, &memoryProducts{})
+	service.Factory = testFactory()
+
+	if _, err := service.Move(s("tools"), s("drills")); err == nil {
+		t.Errorf("Move() expected an error moving into a descendant")
+	}
+
+	category, err := service.Move(s("drills"), s("other"))
+
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+
+	if *category.IsMainCategory != "n" || *category.ParentCategoryID != "other" {
+		t.Errorf("Move() category = %+v", category)
+	}
+
+	tests := []struct {
+		ID        string
+		wantTotal int64
+	}{
+		{ID: "tools", wantTotal: 0},
+		{ID: "power", wantTotal: 0},
+		{ID: "other", wantTotal: 2},
+	}
+	for _, tt := range tests {
+		item, _ := repository.Find(s(tt.ID))
+		if !equals(item.TotalProductCount, tt.wantTotal) {
+			t.Errorf("Move() %s total = %v, want %d", tt.ID, item.TotalProductCount, tt.wantTotal)
+		}
+	}
+}
+
+func TestCategoryService_FindCategory(t *testing.T) {
+	service := NewCategoryService(newMemoryRepository(tree()...), &memoryProducts{})
+
+	if _, err := service.FindCategory(s("tools")); err == nil {
+		t.Errorf("FindCategory() the UUID strategy must reject the ID")
+	} else if _, ok := err.(ids.InvalidIDError); !ok {
+		t.Errorf("FindCategory() error = %T, want ids.InvalidIDError", err)
+	}
+
+	service.Factory = testFactory()
+	category, err := service.NewCategory(s("Garden"), nil, nil, b(true), nil, nil)
+
+	if err != nil || *category.ID != "id-1" || category.CreatedAt.String() != "2019-12-01T00:00:00.000Z" {
+		t.Errorf("NewCategory() category = %+v, error = %v", category, err)
+	}
+}
@@ -0,0 +1,67 @@
+package categories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+)
+
+// TrashCategoryService restores the removed categories and purges the
+// ones that stayed removed longer than the retention period
+type TrashCategoryService struct {
+	Repository CategoryRepository
+	Retention  time.Duration
+	Clock      clock.Clock
+}
+
+func NewTrashCategoryService(repository CategoryRepository, retention time.Duration) *TrashCategoryService {
+	return &TrashCategoryService{
+		Repository: repository,
+		Retention:  retention,
+		Clock:      clock.System,
+	}
+}
+
+func (service *TrashCategoryService) Trash() ([]*Category, error) {
+	return service.Repository.Trash()
+}
+
+// Restore only accepts categories whose parent was not removed as well,
+// the parent must be restored first
+func (service *TrashCategoryService) Restore(ID *string) (*Category, error) {
+	category, err := service.Repository.FindInTrash(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if category == nil {
+		return nil, NotFoundError{ID: *ID}
+	}
+
+	if category.ParentCategoryID != nil && *category.ParentCategoryID != "" {
+		parent, err := service.Repository.Find(category.ParentCategoryID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if parent == nil {
+			return nil, fmt.Errorf("the parent category \"%s\" was removed, restore it first", *category.ParentCategoryID)
+		}
+	}
+
+	if err = service.Repository.Restore(ID); err != nil {
+		return nil, err
+	}
+
+	category.DeletedAt = nil
+
+	return category, nil
+}
+
+// Purge permanently deletes the categories removed before the retention period
+func (service *TrashCategoryService) Purge() ([]*Category, error) {
+	return service.Repository.Purge(service.Clock.Now().Add(-service.Retention))
+}
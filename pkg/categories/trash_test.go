@@ -0,0 +1,53 @@
+package categories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+)
+
+func TestTrashCategoryService(t *testing.T) {
+	now := clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+	repository := newMemoryRepository(
+		&Category{ID: s("tools"), Name: s("Tools"), Visible: b(true)},
+		&Category{ID: s("drills"), Name: s("Drills"), ParentCategoryID: s("tools"), Visible: b(true)},
+		&Category{ID: s("garden"), Name: s("Garden"), Visible: b(true)},
+	)
+	repository.clock = now
+	service := NewTrashCategoryService(repository, 30*24*time.Hour)
+	service.Clock = now
+
+	_ = repository.Remove(s("drills"))
+	_ = repository.Remove(s("tools"))
+	_ = repository.Remove(s("garden"))
+	now.Advance(10 * 24 * time.Hour)
+
+	if found, _ := repository.Find(s("tools")); found != nil {
+		t.Errorf("Find() returned a removed category")
+	}
+
+	if _, err := service.Restore(s("drills")); err == nil {
+		t.Errorf("Restore() expected an error while the parent is removed")
+	}
+
+	if category, err := service.Restore(s("tools")); err != nil || category.DeletedAt != nil {
+		t.Fatalf("Restore() category = %v, error = %v", category, err)
+	}
+
+	if _, err := service.Restore(s("drills")); err != nil {
+		t.Errorf("Restore() error = %v", err)
+	}
+
+	_ = repository.Remove(s("drills"))
+	now.Advance(25 * 24 * time.Hour)
+	purged, err := service.Purge()
+
+	if err != nil || len(purged) != 1 || *purged[0].ID != "garden" {
+		t.Errorf("Purge() purged = %v, error = %v, want only garden", purged, err)
+	}
+
+	if trash, _ := service.Trash(); len(trash) != 1 || *trash[0].ID != "drills" {
+		t.Errorf("Trash() = %v, want only drills", trash)
+	}
+}
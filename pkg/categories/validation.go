@@ -0,0 +1,19 @@
+package categories
+
+import "github.com/alejo-lapix/products-go/pkg/validation"
+
+var validator = validation.New(func(value interface{}) validation.Errors {
+	return validation.NotBlank("name", value.(*Category).Name)
+})
+
+// RegisterRule adds a rule that is checked every time a category is validated
+func RegisterRule(rule func(category *Category) validation.Errors) {
+	validator.Register(func(value interface{}) validation.Errors {
+		return rule(value.(*Category))
+	})
+}
+
+// Validate returns validation.Errors when the category is not valid
+func Validate(category *Category) error {
+	return validator.Validate(category)
+}
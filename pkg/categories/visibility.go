@@ -0,0 +1,47 @@
+package categories
+
+import "fmt"
+
+// Audience tells the listing methods who is going to see the result
+type Audience string
+
+const (
+	// PublicAudience only gets the categories that are effectively visible
+	PublicAudience Audience = "public"
+	// AdminAudience gets every category, including the hidden ones
+	AdminAudience Audience = "admin"
+)
+
+// IsVisible only checks the flag of the category itself,
+// a category without the flag is considered hidden
+func (category *Category) IsVisible() bool {
+	return category.Visible != nil && *category.Visible
+}
+
+// IsEffectivelyVisible walks up the category chain, a category is
+// visible only when it and all of its ancestors are visible
+func IsEffectivelyVisible(repository CategoryRepository, ID *string) (bool, error) {
+	visited := map[string]bool{}
+	current := ID
+
+	for current != nil && *current != "" {
+		if visited[*current] {
+			return false, fmt.Errorf("the category \"%s\" is part of a cycle", *current)
+		}
+
+		visited[*current] = true
+		category, err := repository.Find(current)
+
+		if err != nil {
+			return false, err
+		}
+
+		if category == nil || !category.IsVisible() {
+			return false, nil
+		}
+
+		current = category.ParentCategoryID
+	}
+
+	return true, nil
+}
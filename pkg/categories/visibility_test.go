@@ -0,0 +1,35 @@
+package categories
+
+import "testing"
+
+func TestIsEffectivelyVisible(t *testing.T) {
+	repository := newMemoryRepository(
+		&Category{ID: s("tools"), Visible: b(false)},
+		&Category{ID: s("power"), Visible: b(true), ParentCategoryID: s("tools")},
+		&Category{ID: s("garden"), Visible: b(true)},
+		&Category{ID: s("hoses"), Visible: b(true), ParentCategoryID: s("garden")},
+	)
+	tests := []struct {
+		name    string
+		ID      string
+		want    bool
+		wantErr bool
+	}{
+		{name: "Visible child of a hidden parent", ID: "power", want: false},
+		{name: "Visible child of a visible parent", ID: "hoses", want: true},
+		{name: "Hidden main category", ID: "tools", want: false},
+		{name: "Unknown category", ID: "none", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsEffectivelyVisible(repository, s(tt.ID))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsEffectivelyVisible() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsEffectivelyVisible() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
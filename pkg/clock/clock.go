@@ -0,0 +1,38 @@
+package clock
+
+import "time"
+
+// Clock gives the current time, inject a FixedClock to get deterministic results
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// System is the clock used when nothing else is configured
+var System Clock = SystemClock{}
+
+// FixedClock always returns the same time until it is changed
+type FixedClock struct {
+	Time time.Time
+}
+
+func NewFixedClock(at time.Time) *FixedClock {
+	return &FixedClock{Time: at}
+}
+
+func (clock *FixedClock) Now() time.Time {
+	return clock.Time
+}
+
+func (clock *FixedClock) Set(at time.Time) {
+	clock.Time = at
+}
+
+func (clock *FixedClock) Advance(duration time.Duration) {
+	clock.Time = clock.Time.Add(duration)
+}
@@ -0,0 +1,207 @@
+package dynamo
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// Scan reads every page of the scan into items, a pointer to a slice,
+// a single call of DynamoDB stops at 1 MB of data
+func Scan(db *dynamodb.DynamoDB, input *dynamodb.ScanInput, items interface{}) error {
+	raw := make([]map[string]*dynamodb.AttributeValue, 0)
+	err := db.ScanPages(input, func(page *dynamodb.ScanOutput, last bool) bool {
+		raw = append(raw, page.Items...)
+
+		return true
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return dynamodbattribute.UnmarshalListOfMaps(raw, items)
+}
+
+// Query reads the pages of the query into items, a pointer to a slice, a limit
+// greater than zero stops once that many items passed the filter of the query,
+// the Limit of the input only bounds the items DynamoDB evaluates on every page
+func Query(db *dynamodb.DynamoDB, input *dynamodb.QueryInput, limit int, items interface{}) error {
+	raw := make([]map[string]*dynamodb.AttributeValue, 0)
+	err := db.QueryPages(input, func(page *dynamodb.QueryOutput, last bool) bool {
+		raw = append(raw, page.Items...)
+
+		return limit <= 0 || len(raw) < limit
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if limit > 0 && len(raw) > limit {
+		raw = raw[:limit]
+	}
+
+	return dynamodbattribute.UnmarshalListOfMaps(raw, items)
+}
+
+// batchGetSize is the most keys a single BatchGetItem accepts
+const batchGetSize = 100
+
+// batchGetAttempts bounds the retries of the keys DynamoDB leaves unprocessed
+const batchGetAttempts = 5
+
+// UnprocessedKeysError is returned when DynamoDB keeps throttling some keys of a batch
+type UnprocessedKeysError struct {
+	TableName string
+	Keys      int
+}
+
+func (err UnprocessedKeysError) Error() string {
+	return fmt.Sprintf("%d keys of the table \"%s\" were not processed after %d attempts", err.Keys, err.TableName, batchGetAttempts)
+}
+
+// BatchGet reads the items with the given keys into items, a pointer to a slice, the keys
+// are sent in chunks of 100 and the unprocessed keys are retried with a growing pause, the
+// missing items are skipped, so the result can be shorter and in a different order
+func BatchGet(db *dynamodb.DynamoDB, tableName *string, keys []map[string]*dynamodb.AttributeValue, items interface{}) error {
+	raw := make([]map[string]*dynamodb.AttributeValue, 0, len(keys))
+
+	for _, chunk := range chunks(keys, batchGetSize) {
+		pending := chunk
+
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt == batchGetAttempts {
+				return UnprocessedKeysError{TableName: *tableName, Keys: len(pending)}
+			}
+
+			if attempt > 0 {
+				time.Sleep(time.Duration(attempt*attempt) * 50 * time.Millisecond)
+			}
+
+			output, err := db.BatchGetItem(&dynamodb.BatchGetItemInput{
+				RequestItems: map[string]*dynamodb.KeysAndAttributes{*tableName: {Keys: pending}},
+			})
+
+			if err != nil {
+				return err
+			}
+
+			raw = append(raw, output.Responses[*tableName]...)
+			pending = nil
+
+			if unprocessed := output.UnprocessedKeys[*tableName]; unprocessed != nil {
+				pending = unprocessed.Keys
+			}
+		}
+	}
+
+	return dynamodbattribute.UnmarshalListOfMaps(raw, items)
+}
+
+func chunks(keys []map[string]*dynamodb.AttributeValue, size int) [][]map[string]*dynamodb.AttributeValue {
+	result := make([][]map[string]*dynamodb.AttributeValue, 0, len(keys)/size+1)
+
+	for len(keys) > size {
+		result = append(result, keys[:size])
+		keys = keys[size:]
+	}
+
+	if len(keys) > 0 {
+		result = append(result, keys)
+	}
+
+	return result
+}
+
+// BackfillTimestamps rewrites the given attributes of every item of the table with the
+// sortable timestamp.Layout, the values written with time.RFC3339 before timestamp.Time
+// existed do not sort against the new ones, key is the partition key of the table, it
+// returns how many items were rewritten and can run again after an interruption
+func BackfillTimestamps(db *dynamodb.DynamoDB, tableName *string, key string, attributes ...string) (int, error) {
+	rewritten := 0
+	var updateErr error
+
+	err := db.ScanPages(&dynamodb.ScanInput{TableName: tableName}, func(page *dynamodb.ScanOutput, last bool) bool {
+		for _, item := range page.Items {
+			changed, err := backfill(db, tableName, map[string]*dynamodb.AttributeValue{key: item[key]}, item, attributes)
+
+			if err != nil {
+				updateErr = err
+
+				return false
+			}
+
+			if changed {
+				rewritten++
+			}
+		}
+
+		return true
+	})
+
+	if err == nil {
+		err = updateErr
+	}
+
+	return rewritten, err
+}
+
+func backfill(db *dynamodb.DynamoDB, tableName *string, key, item map[string]*dynamodb.AttributeValue, attributes []string) (bool, error) {
+	expression := ""
+	condition := ""
+	names := map[string]*string{}
+	values := map[string]*dynamodb.AttributeValue{}
+
+	for index, attribute := range attributes {
+		value, ok := item[attribute]
+
+		if !ok || value.S == nil {
+			continue
+		}
+
+		parsed, err := timestamp.Parse(*value.S)
+
+		if err != nil || parsed.String() == *value.S {
+			continue
+		}
+
+		suffix := strconv.Itoa(index)
+		names["#a"+suffix] = aws.String(attribute)
+		values[":new"+suffix] = &dynamodb.AttributeValue{S: aws.String(parsed.String())}
+		values[":old"+suffix] = value
+
+		if expression != "" {
+			expression += ", "
+			condition += " AND "
+		}
+
+		expression += "#a" + suffix + " = :new" + suffix
+		condition += "#a" + suffix + " = :old" + suffix
+	}
+
+	if expression == "" {
+		return false, nil
+	}
+
+	_, err := db.UpdateItem(&dynamodb.UpdateItemInput{
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+		Key:                       key,
+		TableName:                 tableName,
+		UpdateExpression:          aws.String("SET " + expression),
+	})
+
+	// The item was written again meanwhile, with the new layout
+	if ConditionFailed(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
@@ -0,0 +1,42 @@
+package dynamo
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestChunks(t *testing.T) {
+	tests := []struct {
+		name string
+		keys int
+		want []int
+	}{
+		{name: "Empty", keys: 0, want: []int{}},
+		{name: "Single chunk", keys: 100, want: []int{100}},
+		{name: "Several chunks", keys: 250, want: []int{100, 100, 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := make([]map[string]*dynamodb.AttributeValue, tt.keys)
+
+			for index := range keys {
+				keys[index] = map[string]*dynamodb.AttributeValue{"id": {S: aws.String(strconv.Itoa(index))}}
+			}
+
+			got := chunks(keys, batchGetSize)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("chunks() = %d chunks, want %d", len(got), len(tt.want))
+			}
+
+			for index, size := range tt.want {
+				if len(got[index]) != size {
+					t.Errorf("chunks() chunk %d = %d keys, want %d", index, len(got[index]), size)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package dynamo
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ConditionFailed tells if the write was rejected by its condition expression
+func ConditionFailed(err error) bool {
+	awsError, ok := err.(awserr.Error)
+
+	return ok && awsError.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+}
+
+// CancelledBy returns the index of the first item of the transaction rejected by
+// its condition, -1 when the error is not the cancellation of a transaction
+func CancelledBy(err error) int {
+	cancelled, ok := err.(*dynamodb.TransactionCanceledException)
+
+	if !ok {
+		return -1
+	}
+
+	for index, reason := range cancelled.CancellationReasons {
+		if reason != nil && reason.Code != nil && *reason.Code == "ConditionalCheckFailed" {
+			return index
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,28 @@
+package dynamo
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+// Expired reads into items, a pointer to a slice, the items of the records removed by
+// the TTL of the table, the stream must include the old images of the items
+func Expired(records []*dynamodbstreams.Record, items interface{}) error {
+	raw := make([]map[string]*dynamodb.AttributeValue, 0)
+
+	for _, record := range records {
+		if expired(record) && record.Dynamodb != nil && record.Dynamodb.OldImage != nil {
+			raw = append(raw, record.Dynamodb.OldImage)
+		}
+	}
+
+	return dynamodbattribute.UnmarshalListOfMaps(raw, items)
+}
+
+// expired tells the removals of the TTL apart from the ones of the application
+func expired(record *dynamodbstreams.Record) bool {
+	return record.EventName != nil && *record.EventName == dynamodbstreams.OperationTypeRemove &&
+		record.UserIdentity != nil && record.UserIdentity.Type != nil && *record.UserIdentity.Type == "Service" &&
+		record.UserIdentity.PrincipalId != nil && *record.UserIdentity.PrincipalId == "dynamodb.amazonaws.com"
+}
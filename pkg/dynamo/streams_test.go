@@ -0,0 +1,35 @@
+package dynamo
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+func removed(ID string, identity *dynamodbstreams.Identity) *dynamodbstreams.Record {
+	return &dynamodbstreams.Record{
+		EventName:    aws.String(dynamodbstreams.OperationTypeRemove),
+		UserIdentity: identity,
+		Dynamodb: &dynamodbstreams.StreamRecord{
+			OldImage: map[string]*dynamodb.AttributeValue{"id": {S: aws.String(ID)}},
+		},
+	}
+}
+
+func TestExpired(t *testing.T) {
+	ttl := &dynamodbstreams.Identity{PrincipalId: aws.String("dynamodb.amazonaws.com"), Type: aws.String("Service")}
+	records := []*dynamodbstreams.Record{
+		removed("expired", ttl),
+		removed("purged", nil),
+		{EventName: aws.String(dynamodbstreams.OperationTypeModify), UserIdentity: ttl},
+	}
+	items := make([]struct {
+		ID string `json:"id"`
+	}, 0)
+
+	if err := Expired(records, &items); err != nil || len(items) != 1 || items[0].ID != "expired" {
+		t.Errorf("Expired() = %v, error = %v, want only the item removed by the TTL", items, err)
+	}
+}
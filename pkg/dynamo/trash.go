@@ -0,0 +1,57 @@
+package dynamo
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// NotDeleted is part of the filter of every query of the tables with a
+// trash, the deleted items stay in the table until they are purged
+const NotDeleted = "attribute_not_exists(deletedAt)"
+
+// Undelete is the update that takes an item out of the trash
+const Undelete = "REMOVE deletedAt, expiresAt"
+
+// SoftDelete returns the update that moves an item to the trash at the given time, a retention
+// greater than zero also sets the expiresAt attribute, enable the TTL of the table on it, the TTL
+// never runs the Purge of the repositories or their decorators, so consume the stream of the
+// table, with the old images, with Expired and release what the purged items held
+func SoftDelete(at time.Time, retention time.Duration) (*string, map[string]*dynamodb.AttributeValue) {
+	expression := "SET deletedAt = :deletedAt"
+	values := map[string]*dynamodb.AttributeValue{":deletedAt": {S: aws.String(timestamp.Format(at))}}
+
+	if retention > 0 {
+		expression += ", expiresAt = :expiresAt"
+		values[":expiresAt"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(at.Add(retention).Unix(), 10))}
+	}
+
+	return aws.String(expression), values
+}
+
+// Trash reads every deleted item of the table into items, a pointer to a slice
+func Trash(db *dynamodb.DynamoDB, tableName *string, items interface{}) error {
+	return Scan(db, &dynamodb.ScanInput{
+		FilterExpression: aws.String("attribute_exists(deletedAt)"),
+		TableName:        tableName,
+	}, items)
+}
+
+// Purge permanently deletes the item only when it is still in the
+// trash, it returns false when the item was restored in the meantime
+func Purge(db *dynamodb.DynamoDB, tableName *string, key map[string]*dynamodb.AttributeValue) (bool, error) {
+	_, err := db.DeleteItem(&dynamodb.DeleteItemInput{
+		ConditionExpression: aws.String("attribute_exists(deletedAt)"),
+		Key:                 key,
+		TableName:           tableName,
+	})
+
+	if ConditionFailed(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
@@ -0,0 +1,32 @@
+package dynamo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSoftDelete(t *testing.T) {
+	at := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		retention  time.Duration
+		expression string
+		expiresAt  string
+	}{
+		{name: "Without retention", expression: "SET deletedAt = :deletedAt"},
+		{name: "With retention", retention: time.Hour, expression: "SET deletedAt = :deletedAt, expiresAt = :expiresAt", expiresAt: "1583024400"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, values := SoftDelete(at, tt.retention)
+
+			if *expression != tt.expression || values[":deletedAt"] == nil {
+				t.Errorf("SoftDelete() = %s, %v, want %s", *expression, values, tt.expression)
+			}
+
+			if expiresAt := values[":expiresAt"]; (expiresAt == nil) != (tt.expiresAt == "") || expiresAt != nil && *expiresAt.N != tt.expiresAt {
+				t.Errorf("SoftDelete() expiresAt = %v, want %s", expiresAt, tt.expiresAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package ids
+
+import (
+	"fmt"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+)
+
+// Factory has the ID strategy and the clock of the entities, the
+// factories of the categories and the products embed it
+type Factory struct {
+	IDs   Generator
+	Clock clock.Clock
+}
+
+// CheckID fails with InvalidIDError when the ID
+// could not have been created by this factory
+func (factory *Factory) CheckID(ID *string) error {
+	if ID == nil || !factory.IDs.Valid(*ID) {
+		invalid := ""
+
+		if ID != nil {
+			invalid = *ID
+		}
+
+		return InvalidIDError{ID: invalid}
+	}
+
+	return nil
+}
+
+// Sequence creates predictable IDs like "id-1" and accepts
+// any ID that is not empty, it is meant for the tests
+type Sequence struct {
+	next int
+}
+
+func (generator *Sequence) NewID() string {
+	generator.next++
+
+	return fmt.Sprintf("id-%d", generator.next)
+}
+
+func (generator *Sequence) Valid(ID string) bool {
+	return ID != ""
+}
@@ -0,0 +1,123 @@
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/google/uuid"
+)
+
+// Generator creates the IDs of the entities and tells if
+// a given string could have been created by it
+type Generator interface {
+	NewID() string
+	Valid(ID string) bool
+}
+
+// InvalidIDError is returned by the lookups when the requested
+// ID does not match the strategy used to create the entities
+type InvalidIDError struct {
+	ID string
+}
+
+func (err InvalidIDError) Error() string {
+	return fmt.Sprintf("\"%s\" is not a valid ID", err.ID)
+}
+
+// UUIDGenerator creates random version 4 UUIDs
+type UUIDGenerator struct{}
+
+func (UUIDGenerator) NewID() string {
+	return uuid.New().String()
+}
+
+func (UUIDGenerator) Valid(ID string) bool {
+	_, err := uuid.Parse(ID)
+
+	return err == nil && len(ID) == 36
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator creates lexicographically sortable IDs, the first 10
+// characters encode the creation time in milliseconds
+type ULIDGenerator struct {
+	Clock   clock.Clock
+	Entropy io.Reader
+}
+
+func NewULIDGenerator(clock clock.Clock) *ULIDGenerator {
+	return &ULIDGenerator{Clock: clock, Entropy: rand.Reader}
+}
+
+func (generator *ULIDGenerator) NewID() string {
+	var data [16]byte
+	milliseconds := uint64(generator.Clock.Now().UnixNano() / 1000000)
+
+	binary.BigEndian.PutUint16(data[0:2], uint16(milliseconds>>32))
+	binary.BigEndian.PutUint32(data[2:6], uint32(milliseconds))
+
+	if _, err := io.ReadFull(generator.Entropy, data[6:]); err != nil {
+		panic(err)
+	}
+
+	return encode(data)
+}
+
+func (generator *ULIDGenerator) Valid(ID string) bool {
+	if len(ID) != 26 || ID[0] > '7' {
+		return false
+	}
+
+	for _, character := range strings.ToUpper(ID) {
+		if !strings.ContainsRune(crockford, character) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// encode writes the 128 bits as 26 characters of 5 bits, the
+// first character only has the 3 most significant bits
+func encode(data [16]byte) string {
+	result := make([]byte, 26)
+
+	for index := range result {
+		value := 0
+
+		for bit := index*5 - 2; bit < index*5+3; bit++ {
+			value <<= 1
+
+			if bit >= 0 && data[bit/8]&(0x80>>uint(bit%8)) != 0 {
+				value |= 1
+			}
+		}
+
+		result[index] = crockford[value]
+	}
+
+	return string(result)
+}
+
+// PrefixedGenerator adds a prefix like "prod_" to the IDs of another generator
+type PrefixedGenerator struct {
+	Prefix    string
+	Generator Generator
+}
+
+func NewPrefixedGenerator(prefix string, generator Generator) *PrefixedGenerator {
+	return &PrefixedGenerator{Prefix: prefix, Generator: generator}
+}
+
+func (generator *PrefixedGenerator) NewID() string {
+	return generator.Prefix + generator.Generator.NewID()
+}
+
+func (generator *PrefixedGenerator) Valid(ID string) bool {
+	return strings.HasPrefix(ID, generator.Prefix) && generator.Generator.Valid(strings.TrimPrefix(ID, generator.Prefix))
+}
@@ -0,0 +1,75 @@
+package ids
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+)
+
+func TestULIDGenerator_NewID(t *testing.T) {
+	at := clock.NewFixedClock(time.Unix(1469918176, 385000000))
+	generator := &ULIDGenerator{Clock: at, Entropy: bytes.NewReader(make([]byte, 20))}
+	ID := generator.NewID()
+
+	if ID != "01ARYZ6S41"+"0000000000000000" {
+		t.Errorf("NewID() = %s, want the timestamp 01ARYZ6S41 and empty entropy", ID)
+	}
+
+	at.Advance(time.Millisecond)
+	next := generator.NewID()
+
+	if next <= ID {
+		t.Errorf("NewID() = %s, must sort after %s", next, ID)
+	}
+}
+
+func TestGenerator_Valid(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator Generator
+		ID        string
+		want      bool
+	}{
+		{name: "UUID", generator: UUIDGenerator{}, ID: "8b2e5b1c-6a0a-4c1e-9d2f-1c2b3a4d5e6f", want: true},
+		{name: "Not a UUID", generator: UUIDGenerator{}, ID: "abcd", want: false},
+		{name: "ULID", generator: &ULIDGenerator{}, ID: "01ARYZ6S41TSV4RRFFQ69G5FAV", want: true},
+		{name: "ULID with invalid characters", generator: &ULIDGenerator{}, ID: "01ARYZ6S41TSV4RRFFQ69G5FAU", want: false},
+		{name: "Prefixed", generator: NewPrefixedGenerator("prod_", &ULIDGenerator{}), ID: "prod_01ARYZ6S41TSV4RRFFQ69G5FAV", want: true},
+		{name: "Wrong prefix", generator: NewPrefixedGenerator("prod_", &ULIDGenerator{}), ID: "cat_01ARYZ6S41TSV4RRFFQ69G5FAV", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.generator.Valid(tt.ID); got != tt.want {
+				t.Errorf("Valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFactory_CheckID(t *testing.T) {
+	factory := &Factory{IDs: UUIDGenerator{}}
+	tests := []struct {
+		name    string
+		ID      *string
+		wantErr bool
+	}{
+		{name: "Created by the generator", ID: s("8b2e5b1c-6a0a-4c1e-9d2f-1c2b3a4d5e6f")},
+		{name: "Other strategy", ID: s("01ARYZ6S41TSV4RRFFQ69G5FAV"), wantErr: true},
+		{name: "Missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := factory.CheckID(tt.ID)
+
+			if _, ok := err.(InvalidIDError); ok != tt.wantErr {
+				t.Errorf("CheckID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func s(input string) *string {
+	return &input
+}
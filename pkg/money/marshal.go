@@ -0,0 +1,83 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// stored has the same attributes without the custom unmarshalling
+type stored Money
+
+// LegacyCurrencyError is returned when a price stored as a plain
+// number is resolved without the currency of the old prices
+type LegacyCurrencyError struct {
+	Amount float64
+}
+
+func (err LegacyCurrencyError) Error() string {
+	return fmt.Sprintf("the price %v was stored without a currency, configure the legacy currency to read it", err.Amount)
+}
+
+// IsLegacy reports whether the amount was read from a plain number and still needs its currency
+func (money Money) IsLegacy() bool {
+	return money.legacy != nil
+}
+
+// ResolveLegacy gives the currency to an amount read from a plain number, the old prices
+// are in major units of that currency, the rest of the amounts are left unchanged
+func (money *Money) ResolveLegacy(currency Currency) error {
+	if money.legacy == nil {
+		return nil
+	}
+
+	if !currency.Valid() {
+		return LegacyCurrencyError{Amount: *money.legacy}
+	}
+
+	*money = *FromFloat(*money.legacy, currency)
+
+	return nil
+}
+
+// UnmarshalJSON also reads the plain numbers of the old prices,
+// they have no currency until ResolveLegacy is called
+func (money *Money) UnmarshalJSON(data []byte) error {
+	var legacy float64
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		*money = Money{legacy: &legacy}
+
+		return nil
+	}
+
+	return json.Unmarshal(data, (*stored)(money))
+}
+
+// UnmarshalDynamoDBAttributeValue also reads the numbers of the old prices, see UnmarshalJSON
+func (money *Money) UnmarshalDynamoDBAttributeValue(value *dynamodb.AttributeValue) error {
+	if value.N != nil {
+		legacy, err := strconv.ParseFloat(*value.N, 64)
+
+		if err != nil {
+			return err
+		}
+
+		*money = Money{legacy: &legacy}
+
+		return nil
+	}
+
+	if value.M == nil {
+		return nil
+	}
+
+	return dynamodbattribute.UnmarshalMap(value.M, (*stored)(money))
+}
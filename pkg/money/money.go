@@ -0,0 +1,139 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Currency is an ISO 4217 code like USD
+type Currency string
+
+// exponents has the currencies whose minor unit is not the hundredth
+var exponents = map[Currency]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// Exponent is the number of decimals of the currency
+func (currency Currency) Exponent() int {
+	if exponent, ok := exponents[currency]; ok {
+		return exponent
+	}
+
+	return 2
+}
+
+// Valid only checks the format of the code, three uppercase letters
+func (currency Currency) Valid() bool {
+	if len(currency) != 3 {
+		return false
+	}
+
+	for _, character := range currency {
+		if character < 'A' || character > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Money is an amount in the minor unit of the currency, 1250 USD is $12.50
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency" validate:"required,len=3"`
+	// legacy is the plain number read from the prices stored before Money
+	// existed, it waits for its currency in ResolveLegacy
+	legacy *float64
+}
+
+func New(amount int64, currency Currency) *Money {
+	return &Money{Amount: amount, Currency: currency}
+}
+
+// FromFloat converts an amount in major units with the rounding policy of the currency
+func FromFloat(value float64, currency Currency) *Money {
+	scaled := value * math.Pow10(currency.Exponent())
+
+	return New(RoundingFor(currency).Round(scaled), currency)
+}
+
+// Float returns the amount in major units, only use it to display the amount
+func (money Money) Float() float64 {
+	return float64(money.Amount) / math.Pow10(money.Currency.Exponent())
+}
+
+func (money Money) String() string {
+	return strconv.FormatFloat(money.Float(), 'f', money.Currency.Exponent(), 64) + " " + string(money.Currency)
+}
+
+func (money Money) IsZero() bool {
+	return money.Amount == 0
+}
+
+func (money Money) IsNegative() bool {
+	return money.Amount < 0
+}
+
+// CurrencyMismatchError is returned when two amounts of different currencies are combined
+type CurrencyMismatchError struct {
+	Expected Currency
+	Given    Currency
+}
+
+func (err CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("expected an amount in %s, got one in %s", err.Expected, err.Given)
+}
+
+func (money Money) check(other *Money) error {
+	if money.Currency != other.Currency {
+		return CurrencyMismatchError{Expected: money.Currency, Given: other.Currency}
+	}
+
+	return nil
+}
+
+func (money Money) Add(other *Money) (*Money, error) {
+	if err := money.check(other); err != nil {
+		return nil, err
+	}
+
+	return New(money.Amount+other.Amount, money.Currency), nil
+}
+
+func (money Money) Sub(other *Money) (*Money, error) {
+	if err := money.check(other); err != nil {
+		return nil, err
+	}
+
+	return New(money.Amount-other.Amount, money.Currency), nil
+}
+
+// Multiply rounds the result with the rounding policy of the currency
+func (money Money) Multiply(factor float64) *Money {
+	return New(RoundingFor(money.Currency).Round(float64(money.Amount)*factor), money.Currency)
+}
+
+// Compare returns -1, 0 or 1 when the amount is lower, equal or greater than the other one
+func (money Money) Compare(other *Money) (int, error) {
+	if err := money.check(other); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case money.Amount < other.Amount:
+		return -1, nil
+	case money.Amount > other.Amount:
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// Round applies the rounding policy of the currency to the amount,
+// useful for currencies whose policy has an increment
+func (money Money) Round() *Money {
+	return New(RoundingFor(money.Currency).Round(float64(money.Amount)), money.Currency)
+}
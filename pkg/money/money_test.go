@@ -0,0 +1,147 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		currency Currency
+		want     string
+	}{
+		{name: "Cents", value: 0.29, currency: "USD", want: "0.29 USD"},
+		{name: "Currencies without decimals", value: 1500.5, currency: "CLP", want: "1501 CLP"},
+		{name: "Currencies with three decimals", value: 1.2345, currency: "KWD", want: "1.235 KWD"},
+		{name: "Negative amounts", value: -10.005, currency: "EUR", want: "-10.01 EUR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromFloat(tt.value, tt.currency).String(); got != tt.want {
+				t.Errorf("FromFloat() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundingPolicy_Round(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RoundingPolicy
+		minor  float64
+		want   int64
+	}{
+		{name: "Half up", policy: RoundingPolicy{Mode: HalfUp}, minor: 2.5, want: 3},
+		{name: "Half even", policy: RoundingPolicy{Mode: HalfEven}, minor: 2.5, want: 2},
+		{name: "Down ignores the float noise", policy: RoundingPolicy{Mode: Down}, minor: 0.29 * 100, want: 29},
+		{name: "Up", policy: RoundingPolicy{Mode: Up}, minor: -2.1, want: -3},
+		{name: "Increments", policy: RoundingPolicy{Mode: HalfUp, Increment: 50}, minor: 12575, want: 12600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Round(tt.minor); got != tt.want {
+				t.Errorf("Round() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetRounding(t *testing.T) {
+	SetRounding("CHF", RoundingPolicy{Mode: HalfUp, Increment: 5})
+	defer SetRounding("CHF", DefaultRounding)
+
+	if got := FromFloat(10.12, "CHF").String(); got != "10.10 CHF" {
+		t.Errorf("FromFloat() = %s, want 10.10 CHF", got)
+	}
+
+	if got := New(1000, "CHF").Multiply(1.0133).String(); got != "10.15 CHF" {
+		t.Errorf("Multiply() = %s, want 10.15 CHF", got)
+	}
+}
+
+func TestMoney_Add(t *testing.T) {
+	if _, err := New(100, "USD").Add(New(100, "EUR")); err == nil {
+		t.Errorf("Add() expected a CurrencyMismatchError")
+	}
+
+	if sum, err := New(100, "USD").Add(New(250, "USD")); err != nil || sum.Amount != 350 {
+		t.Errorf("Add() = %v, error = %v", sum, err)
+	}
+}
+
+type priced struct {
+	Price *Money `json:"price"`
+}
+
+func TestMoney_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		// the old prices are read in the currency given to ResolveLegacy
+		{name: "Old float prices", json: `{"price":5000.5}`, want: "5000.50 COP"},
+		{name: "Money prices", json: `{"price":{"amount":1250,"currency":"USD"}}`, want: "12.50 USD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fromJSON := &priced{}
+
+			err := json.Unmarshal([]byte(tt.json), fromJSON)
+
+			if err == nil {
+				err = fromJSON.Price.ResolveLegacy("COP")
+			}
+
+			if err != nil || fromJSON.Price.String() != tt.want {
+				t.Errorf("UnmarshalJSON() = %v, error = %v, want %s", fromJSON.Price, err, tt.want)
+			}
+
+			var document map[string]interface{}
+			_ = json.Unmarshal([]byte(tt.json), &document)
+			item, _ := dynamodbattribute.MarshalMap(document)
+			fromDynamoDB := &priced{}
+
+			err = dynamodbattribute.UnmarshalMap(item, fromDynamoDB)
+
+			if err == nil {
+				err = fromDynamoDB.Price.ResolveLegacy("COP")
+			}
+
+			if err != nil || fromDynamoDB.Price.String() != tt.want {
+				t.Errorf("UnmarshalDynamoDBAttributeValue() = %v, error = %v, want %s", fromDynamoDB.Price, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_ResolveLegacy(t *testing.T) {
+	price := &priced{}
+	_ = json.Unmarshal([]byte(`{"price":5000.5}`), price)
+
+	if !price.Price.IsLegacy() {
+		t.Fatalf("IsLegacy() = false, want true for a plain number")
+	}
+
+	if err, ok := price.Price.ResolveLegacy("").(LegacyCurrencyError); !ok || err.Amount != 5000.5 {
+		t.Errorf("ResolveLegacy() error = %v, want a LegacyCurrencyError", err)
+	}
+
+	if err := price.Price.ResolveLegacy("USD"); err != nil || *price.Price != *New(500050, "USD") || price.Price.IsLegacy() {
+		t.Errorf("ResolveLegacy() = %v, error = %v, want 5000.50 USD", price.Price, err)
+	}
+}
+
+func TestPrices_Set(t *testing.T) {
+	prices := Prices{New(100, "USD")}
+	prices.Set(New(90, "EUR"))
+	prices.Set(New(120, "USD"))
+
+	if len(prices) != 2 || prices.In("USD").Amount != 120 || prices.In("GBP") != nil {
+		t.Errorf("Set() prices = %v", prices)
+	}
+}
@@ -0,0 +1,52 @@
+package money
+
+// Prices has at most one amount per currency
+type Prices []*Money
+
+// In returns the amount in the currency, nil when there is none
+func (prices Prices) In(currency Currency) *Money {
+	for _, price := range prices {
+		if price != nil && price.Currency == currency {
+			return price
+		}
+	}
+
+	return nil
+}
+
+// Set replaces the amount of the same currency or adds it
+func (prices *Prices) Set(price *Money) {
+	for index, current := range *prices {
+		if current != nil && current.Currency == price.Currency {
+			(*prices)[index] = price
+
+			return
+		}
+	}
+
+	*prices = append(*prices, price)
+}
+
+func (prices *Prices) Remove(currency Currency) bool {
+	for index, current := range *prices {
+		if current != nil && current.Currency == currency {
+			*prices = append((*prices)[:index], (*prices)[index+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func (prices Prices) Currencies() []Currency {
+	currencies := make([]Currency, 0, len(prices))
+
+	for _, price := range prices {
+		if price != nil {
+			currencies = append(currencies, price.Currency)
+		}
+	}
+
+	return currencies
+}
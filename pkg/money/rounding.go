@@ -0,0 +1,81 @@
+package money
+
+import (
+	"math"
+	"sync"
+)
+
+type Mode int
+
+const (
+	// HalfUp rounds the halves away from zero
+	HalfUp Mode = iota
+	// HalfEven rounds the halves to the nearest even number
+	HalfEven
+	// Down rounds towards zero
+	Down
+	// Up rounds away from zero
+	Up
+)
+
+// RoundingPolicy turns a fractional amount of minor units into a whole amount that
+// is a multiple of Increment, an Increment of 50 on COP only gives multiples of 50 cents
+type RoundingPolicy struct {
+	Mode      Mode
+	Increment int64
+}
+
+// DefaultRounding is used by the currencies without a policy
+var DefaultRounding = RoundingPolicy{Mode: HalfUp, Increment: 1}
+
+func (policy RoundingPolicy) Round(minor float64) int64 {
+	increment := policy.Increment
+
+	if increment < 1 {
+		increment = 1
+	}
+
+	// Removes the noise of the float operations, 0.29 * 100 is 28.999999999999996
+	units := math.Round(minor/float64(increment)*1e6) / 1e6
+
+	switch policy.Mode {
+	case HalfEven:
+		units = math.RoundToEven(units)
+	case Down:
+		units = math.Trunc(units)
+	case Up:
+		if units < 0 {
+			units = math.Floor(units)
+		} else {
+			units = math.Ceil(units)
+		}
+	default:
+		units = math.Round(units)
+	}
+
+	return int64(units) * increment
+}
+
+var (
+	mutex    sync.RWMutex
+	policies = map[Currency]RoundingPolicy{}
+)
+
+// SetRounding configures the rounding policy of the currency
+func SetRounding(currency Currency, policy RoundingPolicy) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	policies[currency] = policy
+}
+
+func RoundingFor(currency Currency) RoundingPolicy {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	if policy, ok := policies[currency]; ok {
+		return policy
+	}
+
+	return DefaultRounding
+}
@@ -0,0 +1,122 @@
+package multimedia
+
+import (
+	"fmt"
+
+	"github.com/alejo-lapix/multimedia-go/persistence"
+	"github.com/alejo-lapix/products-go/pkg/ordering"
+)
+
+// Collection is the ordered list of multimedia items of a product or a
+// category, the first item of the list is the cover of its owner
+type Collection []*persistence.MultimediaItem
+
+func (collection Collection) indexOf(ID *string) int {
+	for index, item := range collection {
+		if item != nil && item.ID != nil && *item.ID == *ID {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func (collection Collection) Has(ID *string) bool {
+	return collection.indexOf(ID) >= 0
+}
+
+// Cover returns the main item of the collection
+func (collection Collection) Cover() *persistence.MultimediaItem {
+	if len(collection) == 0 {
+		return nil
+	}
+
+	return collection[0]
+}
+
+func (collection Collection) IDs() []*string {
+	ids := make([]*string, 0, len(collection))
+
+	for _, item := range collection {
+		if item != nil {
+			ids = append(ids, item.ID)
+		}
+	}
+
+	return ids
+}
+
+// Add appends the item at the end of the collection
+func (collection *Collection) Add(item *persistence.MultimediaItem) error {
+	if item == nil || item.ID == nil {
+		return fmt.Errorf("the multimedia item must have an ID")
+	}
+
+	if collection.Has(item.ID) {
+		return fmt.Errorf("the multimedia item \"%s\" is already part of the collection", *item.ID)
+	}
+
+	*collection = append(*collection, item)
+
+	return nil
+}
+
+func (collection *Collection) Remove(ID *string) bool {
+	index := collection.indexOf(ID)
+
+	if index < 0 {
+		return false
+	}
+
+	list := make(Collection, 0, len(*collection)-1)
+	list = append(list, (*collection)[:index]...)
+	*collection = append(list, (*collection)[index+1:]...)
+
+	return true
+}
+
+// Replace puts the new item in the position of the one with the given ID
+func (collection *Collection) Replace(ID *string, item *persistence.MultimediaItem) bool {
+	index := collection.indexOf(ID)
+
+	if index < 0 || item == nil {
+		return false
+	}
+
+	(*collection)[index] = item
+
+	return true
+}
+
+// SetCover moves the item to the first position of the collection
+func (collection *Collection) SetCover(ID *string) bool {
+	index := collection.indexOf(ID)
+
+	if index < 0 {
+		return false
+	}
+
+	item := (*collection)[index]
+	copy((*collection)[1:index+1], (*collection)[:index])
+	(*collection)[0] = item
+
+	return true
+}
+
+// Reorder sorts the collection following the given IDs,
+// the list must contain all the items of the collection
+func (collection *Collection) Reorder(ids []*string) error {
+	if err := ordering.Validate(collection.IDs(), ids); err != nil {
+		return err
+	}
+
+	list := make(Collection, len(ids))
+
+	for index, id := range ids {
+		list[index] = (*collection)[collection.indexOf(id)]
+	}
+
+	*collection = list
+
+	return nil
+}
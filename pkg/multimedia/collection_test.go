@@ -0,0 +1,100 @@
+package multimedia
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alejo-lapix/multimedia-go/persistence"
+)
+
+func s(input string) *string {
+	return &input
+}
+
+func collection(ids ...string) Collection {
+	result := make(Collection, len(ids))
+
+	for index, id := range ids {
+		result[index] = &persistence.MultimediaItem{ID: s(id)}
+	}
+
+	return result
+}
+
+func values(collection Collection) []string {
+	result := make([]string, len(collection))
+
+	for index, item := range collection {
+		result[index] = *item.ID
+	}
+
+	return result
+}
+
+func TestCollection(t *testing.T) {
+	tests := []struct {
+		name    string
+		operate func(collection *Collection) bool
+		want    []string
+		wantOk  bool
+	}{
+		{
+			name:    "Removes an item",
+			operate: func(collection *Collection) bool { return collection.Remove(s("b")) },
+			want:    []string{"a", "c"},
+			wantOk:  true,
+		},
+		{
+			name:    "Does not remove unknown items",
+			operate: func(collection *Collection) bool { return collection.Remove(s("x")) },
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:    "Moves the cover to the first position",
+			operate: func(collection *Collection) bool { return collection.SetCover(s("c")) },
+			want:    []string{"c", "a", "b"},
+			wantOk:  true,
+		},
+		{
+			name: "Replaces an item in the same position",
+			operate: func(collection *Collection) bool {
+				return collection.Replace(s("b"), &persistence.MultimediaItem{ID: s("z")})
+			},
+			want:   []string{"a", "z", "c"},
+			wantOk: true,
+		},
+		{
+			name: "Reorders the whole collection",
+			operate: func(collection *Collection) bool {
+				return collection.Reorder([]*string{s("b"), s("c"), s("a")}) == nil
+			},
+			want:   []string{"b", "c", "a"},
+			wantOk: true,
+		},
+		{
+			name: "Does not accept a partial order",
+			operate: func(collection *Collection) bool {
+				return collection.Reorder([]*string{s("b")}) == nil
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "Does not add repeated items",
+			operate: func(collection *Collection) bool {
+				return collection.Add(&persistence.MultimediaItem{ID: s("a")}) == nil
+			},
+			want: []string{"a", "b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := collection("a", "b", "c")
+			if ok := tt.operate(&items); ok != tt.wantOk {
+				t.Errorf("operation ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !reflect.DeepEqual(values(items), tt.want) {
+				t.Errorf("collection = %v, want %v", values(items), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package multimedia
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// DynamoDBOwners tells which items are still used by the items of a table, it reads every
+// page of the base table, the deleted items included, projecting only the given attributes,
+// any attribute named multimedia found under them is a reference to the store
+type DynamoDBOwners struct {
+	DynamoDB   *dynamodb.DynamoDB
+	TableName  *string
+	Attributes []string
+}
+
+func NewDynamoDBOwners(db *dynamodb.DynamoDB, tableName string, attributes ...string) *DynamoDBOwners {
+	return &DynamoDBOwners{DynamoDB: db, TableName: aws.String(tableName), Attributes: attributes}
+}
+
+func (owners *DynamoDBOwners) Referenced(IDs []string) (map[string]bool, error) {
+	wanted := make(map[string]bool, len(IDs))
+	result := map[string]bool{}
+
+	for _, ID := range IDs {
+		wanted[ID] = true
+	}
+
+	names := make(map[string]*string, len(owners.Attributes))
+	projection := make([]string, len(owners.Attributes))
+
+	for index, attribute := range owners.Attributes {
+		name := "#a" + strconv.Itoa(index)
+		names[name] = aws.String(attribute)
+		projection[index] = name
+	}
+
+	err := owners.DynamoDB.ScanPages(&dynamodb.ScanInput{
+		ExpressionAttributeNames: names,
+		ProjectionExpression:     aws.String(strings.Join(projection, ", ")),
+		TableName:                owners.TableName,
+	}, func(page *dynamodb.ScanOutput, last bool) bool {
+		for _, item := range page.Items {
+			referencedIn(item, wanted, result)
+		}
+
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// referencedIn walks the attributes looking for the multimedia items, a
+// multimedia attribute is either a single item or a list of them
+func referencedIn(attributes map[string]*dynamodb.AttributeValue, wanted, result map[string]bool) {
+	for name, value := range attributes {
+		if value == nil {
+			continue
+		}
+
+		if name == "multimedia" {
+			items := value.L
+
+			if value.M != nil {
+				items = []*dynamodb.AttributeValue{value}
+			}
+
+			for _, item := range items {
+				if item == nil || item.M == nil || item.M["id"] == nil || item.M["id"].S == nil {
+					continue
+				}
+
+				if ID := *item.M["id"].S; wanted[ID] {
+					result[ID] = true
+				}
+			}
+
+			continue
+		}
+
+		if value.M != nil {
+			referencedIn(value.M, wanted, result)
+		}
+
+		for _, element := range value.L {
+			if element != nil && element.M != nil {
+				referencedIn(element.M, wanted, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package multimedia
+
+import "github.com/alejo-lapix/multimedia-go/persistence"
+
+// Store is the place where the multimedia items live, the
+// persistence.AWSPersistenceManager of multimedia-go satisfies it
+type Store interface {
+	Find(ID *string) (*persistence.MultimediaItem, error)
+	Remove(ID *string) error
+}
+
+// Lister is implemented by the stores that are able to list all their
+// items, the Scanner needs it to report the orphaned items
+type Lister interface {
+	All() ([]*persistence.MultimediaItem, error)
+}
+
+// Owners tells which of the given items are still used by somebody, the
+// products and the categories implement it over their repositories
+type Owners interface {
+	Referenced(IDs []string) (map[string]bool, error)
+}
+
+// Releaser removes from the store the items of a deleted product or category,
+// the items still referenced by any of the Owners are kept, so the items
+// shared by several products or categories live until the last one is gone
+type Releaser struct {
+	Store  Store
+	Owners []Owners
+}
+
+func NewReleaser(store Store, owners ...Owners) *Releaser {
+	return &Releaser{Store: store, Owners: owners}
+}
+
+func (releaser *Releaser) Release(items Collection) error {
+	orphaned, err := releaser.orphaned(items)
+
+	if err != nil {
+		return err
+	}
+
+	for _, ID := range orphaned {
+		if err = releaser.Store.Remove(&ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// orphaned returns the IDs of the items that none of the owners references
+func (releaser *Releaser) orphaned(items Collection) ([]string, error) {
+	IDs := make([]string, 0, len(items))
+	seen := map[string]bool{}
+
+	for _, item := range items {
+		if item == nil || item.ID == nil || seen[*item.ID] {
+			continue
+		}
+
+		seen[*item.ID] = true
+		IDs = append(IDs, *item.ID)
+	}
+
+	for _, owners := range releaser.Owners {
+		if len(IDs) == 0 {
+			break
+		}
+
+		referenced, err := owners.Referenced(IDs)
+
+		if err != nil {
+			return nil, err
+		}
+
+		pending := IDs[:0]
+
+		for _, ID := range IDs {
+			if !referenced[ID] {
+				pending = append(pending, ID)
+			}
+		}
+
+		IDs = pending
+	}
+
+	return IDs, nil
+}
+
+// ReferencedBy returns which of the IDs are used by the collections
+func ReferencedBy(IDs []string, collections ...Collection) map[string]bool {
+	wanted := make(map[string]bool, len(IDs))
+	result := map[string]bool{}
+
+	for _, ID := range IDs {
+		wanted[ID] = true
+	}
+
+	for _, collection := range collections {
+		for _, item := range collection {
+			if item != nil && item.ID != nil && wanted[*item.ID] {
+				result[*item.ID] = true
+			}
+		}
+	}
+
+	return result
+}
+
+// Reference is a multimedia item used by a product or a category
+type Reference struct {
+	OwnerType string                      `json:"ownerType"`
+	OwnerID   *string                     `json:"ownerId"`
+	Item      *persistence.MultimediaItem `json:"item"`
+}
+
+func NewReferences(ownerType string, ownerID *string, items Collection) []*Reference {
+	references := make([]*Reference, 0, len(items))
+
+	for _, item := range items {
+		if item != nil && item.ID != nil {
+			references = append(references, &Reference{OwnerType: ownerType, OwnerID: ownerID, Item: item})
+		}
+	}
+
+	return references
+}
+
+type ScanReport struct {
+	// Broken are the references to items that no longer exist in the store
+	Broken []*Reference `json:"broken"`
+	// Orphaned are the items of the store nobody references, it is
+	// only filled when the store implements Lister
+	Orphaned []*persistence.MultimediaItem `json:"orphaned"`
+}
+
+type Scanner struct {
+	Store Store
+}
+
+func NewScanner(store Store) *Scanner {
+	return &Scanner{Store: store}
+}
+
+func (scanner *Scanner) Scan(references []*Reference) (*ScanReport, error) {
+	report := &ScanReport{
+		Broken:   []*Reference{},
+		Orphaned: []*persistence.MultimediaItem{},
+	}
+	found := map[string]bool{}
+	referenced := map[string]bool{}
+
+	for _, reference := range references {
+		id := *reference.Item.ID
+		referenced[id] = true
+		exists, ok := found[id]
+
+		if !ok {
+			item, err := scanner.Store.Find(reference.Item.ID)
+
+			if err != nil {
+				return nil, err
+			}
+
+			exists = item != nil
+			found[id] = exists
+		}
+
+		if !exists {
+			report.Broken = append(report.Broken, reference)
+		}
+	}
+
+	lister, ok := scanner.Store.(Lister)
+
+	if !ok {
+		return report, nil
+	}
+
+	items, err := lister.All()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.ID != nil && !referenced[*item.ID] {
+			report.Orphaned = append(report.Orphaned, item)
+		}
+	}
+
+	return report, nil
+}
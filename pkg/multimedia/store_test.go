@@ -0,0 +1,128 @@
+package multimedia
+
+import (
+	"testing"
+
+	"github.com/alejo-lapix/multimedia-go/persistence"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+type memoryStore struct {
+	items map[string]*persistence.MultimediaItem
+}
+
+func (store *memoryStore) Find(ID *string) (*persistence.MultimediaItem, error) {
+	return store.items[*ID], nil
+}
+
+func (store *memoryStore) Remove(ID *string) error {
+	delete(store.items, *ID)
+
+	return nil
+}
+
+func (store *memoryStore) All() ([]*persistence.MultimediaItem, error) {
+	items := make([]*persistence.MultimediaItem, 0, len(store.items))
+
+	for _, item := range store.items {
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func TestScanner_Scan(t *testing.T) {
+	store := &memoryStore{items: map[string]*persistence.MultimediaItem{}}
+
+	for _, item := range collection("a", "b", "orphan") {
+		store.items[*item.ID] = item
+	}
+
+	references := append(
+		NewReferences("product", s("p1"), collection("a", "missing")),
+		NewReferences("category", s("c1"), collection("b", "a"))...,
+	)
+
+	report, err := NewScanner(store).Scan(references)
+
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if len(report.Broken) != 1 || *report.Broken[0].Item.ID != "missing" || *report.Broken[0].OwnerID != "p1" {
+		t.Errorf("Scan() broken = %v", report.Broken)
+	}
+
+	if len(report.Orphaned) != 1 || *report.Orphaned[0].ID != "orphan" {
+		t.Errorf("Scan() orphaned = %v", report.Orphaned)
+	}
+}
+
+func TestReleaser_Release(t *testing.T) {
+	store := &memoryStore{items: map[string]*persistence.MultimediaItem{}}
+
+	for _, item := range collection("a", "b", "c") {
+		store.items[*item.ID] = item
+	}
+
+	if err := NewReleaser(store).Release(collection("a", "c")); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	if len(store.items) != 1 || store.items["b"] == nil {
+		t.Errorf("Release() remaining = %v", store.items)
+	}
+}
+
+type memoryOwners struct {
+	collections []Collection
+}
+
+func (owners *memoryOwners) Referenced(IDs []string) (map[string]bool, error) {
+	return ReferencedBy(IDs, owners.collections...), nil
+}
+
+func TestReleaser_ReleaseShared(t *testing.T) {
+	store := &memoryStore{items: map[string]*persistence.MultimediaItem{}}
+
+	for _, item := range collection("shared", "own") {
+		store.items[*item.ID] = item
+	}
+
+	// The first product is gone, the second one still uses the shared item
+	products := &memoryOwners{collections: []Collection{collection("shared")}}
+	categories := &memoryOwners{}
+
+	if err := NewReleaser(store, categories, products).Release(collection("shared", "own", "shared")); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	if len(store.items) != 1 || store.items["shared"] == nil {
+		t.Errorf("Release() remaining = %v, want the shared item", store.items)
+	}
+
+	products.collections = nil
+
+	if err := NewReleaser(store, categories, products).Release(collection("shared")); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	if len(store.items) != 0 {
+		t.Errorf("Release() remaining = %v, want nothing once the last owner is gone", store.items)
+	}
+}
+
+func TestReferencedIn(t *testing.T) {
+	item, _ := dynamodbattribute.MarshalMap(map[string]interface{}{
+		"multimedia": collection("cover"),
+		"variants":   []map[string]interface{}{{"multimedia": collection("variant")}},
+		"banner":     map[string]interface{}{"multimedia": &persistence.MultimediaItem{ID: s("banner")}},
+		"banners":    []map[string]interface{}{{"banner": map[string]interface{}{"multimedia": nil}}},
+	})
+	result := map[string]bool{}
+	referencedIn(item, map[string]bool{"cover": true, "variant": true, "banner": true, "orphan": true}, result)
+
+	if len(result) != 3 || !result["cover"] || !result["variant"] || !result["banner"] {
+		t.Errorf("referencedIn() = %v, want all but the orphan", result)
+	}
+}
@@ -0,0 +1,105 @@
+package ordering
+
+import "fmt"
+
+// Key holds the values used to sort items that can be manually ordered,
+// items without a position go after the positioned ones and ties are
+// resolved by name and then by ID so the result is always stable
+type Key struct {
+	Position *int
+	Name     *string
+	ID       *string
+}
+
+func Less(a, b Key) bool {
+	if a.Position != nil && b.Position != nil && *a.Position != *b.Position {
+		return *a.Position < *b.Position
+	}
+
+	if (a.Position == nil) != (b.Position == nil) {
+		return a.Position != nil
+	}
+
+	if name, other := value(a.Name), value(b.Name); name != other {
+		return name < other
+	}
+
+	return value(a.ID) < value(b.ID)
+}
+
+func value(input *string) string {
+	if input == nil {
+		return ""
+	}
+
+	return *input
+}
+
+// Move returns a copy of ids where id is placed right before or
+// right after the anchor element
+func Move(ids []*string, id, anchor *string, after bool) ([]*string, error) {
+	if *id == *anchor {
+		return nil, fmt.Errorf("the element \"%s\" can not be moved relative to itself", *id)
+	}
+
+	if indexOf(ids, id) < 0 {
+		return nil, fmt.Errorf("the element \"%s\" is not part of the list", *id)
+	}
+
+	result := make([]*string, 0, len(ids))
+
+	for _, current := range ids {
+		if *current != *id {
+			result = append(result, current)
+		}
+	}
+
+	position := indexOf(result, anchor)
+
+	if position < 0 {
+		return nil, fmt.Errorf("the element \"%s\" is not part of the list", *anchor)
+	}
+
+	if after {
+		position++
+	}
+
+	result = append(result, nil)
+	copy(result[position+1:], result[position:])
+	result[position] = id
+
+	return result, nil
+}
+
+// Validate checks that order contains exactly the same elements of ids
+func Validate(ids, order []*string) error {
+	if len(ids) != len(order) {
+		return fmt.Errorf("the new order has %d elements but %d were expected", len(order), len(ids))
+	}
+
+	seen := make(map[string]bool, len(order))
+
+	for _, id := range order {
+		if seen[*id] {
+			return fmt.Errorf("the element \"%s\" is repeated", *id)
+		}
+
+		if indexOf(ids, id) < 0 {
+			return fmt.Errorf("the element \"%s\" is not part of the list", *id)
+		}
+
+		seen[*id] = true
+	}
+
+	return nil
+}
+
+func indexOf(ids []*string, id *string) int {
+	for index, current := range ids {
+		if *current == *id {
+			return index
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,116 @@
+package ordering
+
+import (
+	"reflect"
+	"testing"
+)
+
+func s(input string) *string {
+	return &input
+}
+
+func i(input int) *int {
+	return &input
+}
+
+func values(ids []*string) []string {
+	result := make([]string, len(ids))
+
+	for index, id := range ids {
+		result[index] = *id
+	}
+
+	return result
+}
+
+func TestLess(t *testing.T) {
+	tests := []struct {
+		name string
+		a    Key
+		b    Key
+		want bool
+	}{
+		{
+			name: "Lower position goes first",
+			a:    Key{Position: i(1), Name: s("b")},
+			b:    Key{Position: i(2), Name: s("a")},
+			want: true,
+		},
+		{
+			name: "Positioned items go before the others",
+			a:    Key{Name: s("a")},
+			b:    Key{Position: i(5), Name: s("z")},
+			want: false,
+		},
+		{
+			name: "Same position falls back to the name",
+			a:    Key{Position: i(1), Name: s("a")},
+			b:    Key{Position: i(1), Name: s("b")},
+			want: true,
+		},
+		{
+			name: "Same name falls back to the ID",
+			a:    Key{Name: s("a"), ID: s("2")},
+			b:    Key{Name: s("a"), ID: s("1")},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Less(tt.a, tt.b); got != tt.want {
+				t.Errorf("Less() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		anchor  string
+		after   bool
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "Moves before the anchor",
+			id:     "d",
+			anchor: "b",
+			want:   []string{"a", "d", "b", "c"},
+		},
+		{
+			name:   "Moves after the anchor",
+			id:     "a",
+			anchor: "c",
+			after:  true,
+			want:   []string{"b", "c", "a", "d"},
+		},
+		{
+			name:   "Moves after the last element",
+			id:     "a",
+			anchor: "d",
+			after:  true,
+			want:   []string{"b", "c", "d", "a"},
+		},
+		{
+			name:    "Fails with an unknown anchor",
+			id:      "a",
+			anchor:  "x",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := []*string{s("a"), s("b"), s("c"), s("d")}
+			got, err := Move(ids, s(tt.id), s(tt.anchor), tt.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Move() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(values(got), tt.want) {
+				t.Errorf("Move() got = %v, want %v", values(got), tt.want)
+			}
+		})
+	}
+}
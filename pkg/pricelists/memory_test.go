@@ -0,0 +1,93 @@
+package pricelists
+
+func s(input string) *string {
+	return &input
+}
+
+type memoryLists struct {
+	items map[string]*PriceList
+}
+
+func newMemoryLists(items ...*PriceList) *memoryLists {
+	repository := &memoryLists{items: map[string]*PriceList{}}
+
+	for _, item := range items {
+		repository.items[*item.ID] = item
+	}
+
+	return repository
+}
+
+func (repository *memoryLists) Store(list *PriceList) error {
+	repository.items[*list.ID] = list
+
+	return nil
+}
+
+func (repository *memoryLists) Update(ID *string, list *PriceList) error {
+	repository.items[*ID] = list
+
+	return nil
+}
+
+func (repository *memoryLists) Find(ID *string) (*PriceList, error) {
+	return repository.items[*ID], nil
+}
+
+func (repository *memoryLists) Remove(ID *string) error {
+	delete(repository.items, *ID)
+
+	return nil
+}
+
+func (repository *memoryLists) All() ([]*PriceList, error) {
+	result := make([]*PriceList, 0)
+
+	for _, item := range repository.items {
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+type memoryEntries struct {
+	items map[string]*Entry
+}
+
+func newMemoryEntries(items ...*Entry) *memoryEntries {
+	repository := &memoryEntries{items: map[string]*Entry{}}
+
+	for _, item := range items {
+		repository.Store(item)
+	}
+
+	return repository
+}
+
+func (repository *memoryEntries) Store(entry *Entry) error {
+	repository.items[*entry.PriceListID+"#"+*entry.ProductID] = entry
+
+	return nil
+}
+
+func (repository *memoryEntries) Find(priceListID, productID *string) (*Entry, error) {
+	return repository.items[*priceListID+"#"+*productID], nil
+}
+
+func (repository *memoryEntries) FindByPriceListID(priceListID *string) ([]*Entry, error) {
+	result := make([]*Entry, 0)
+
+	for _, item := range repository.items {
+		if *item.PriceListID == *priceListID {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (repository *memoryEntries) Remove(priceListID, productID *string) error {
+	delete(repository.items, *priceListID+"#"+*productID)
+
+	return nil
+}
@@ -0,0 +1,227 @@
+package pricelists
+
+import (
+	"fmt"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/validation"
+)
+
+type Kind string
+
+const (
+	// CustomerGroup lists price the products for a group of customers, like wholesale
+	CustomerGroup Kind = "customerGroup"
+	// Channel lists price the products for a sales channel, like a marketplace
+	Channel Kind = "channel"
+)
+
+// PriceList is a named set of prices, all of them in the currency of the list,
+// Audience identifies the customer group or the channel that uses the list
+type PriceList struct {
+	ID       *string        `json:"id"`
+	Name     *string        `json:"name" validate:"required"`
+	Kind     Kind           `json:"kind" validate:"oneof=customerGroup channel"`
+	Audience *string        `json:"audience,omitempty"`
+	Currency money.Currency `json:"currency"`
+}
+
+// Tier is the unit price from MinQuantity units on
+type Tier struct {
+	MinQuantity int64        `json:"minQuantity"`
+	Price       *money.Money `json:"price"`
+}
+
+// Entry overrides the price of a product inside a list, Price is the unit price
+// for any quantity and the tiers replace it from their minimum quantity on, the
+// variants override the price of the variants of the product with the same rules
+type Entry struct {
+	PriceListID *string         `json:"priceListId"`
+	ProductID   *string         `json:"productId"`
+	Price       *money.Money    `json:"price,omitempty"`
+	Tiers       []*Tier         `json:"tiers,omitempty"`
+	Variants    []*VariantEntry `json:"variants,omitempty"`
+}
+
+// VariantEntry overrides the price of the variant with the SKU inside the list
+type VariantEntry struct {
+	SKU   *string      `json:"sku"`
+	Price *money.Money `json:"price,omitempty"`
+	Tiers []*Tier      `json:"tiers,omitempty"`
+}
+
+// TierFor returns the tier with the highest minimum quantity
+// that the quantity reaches, nil when it reaches none of them
+func (entry *Entry) TierFor(quantity int64) *Tier {
+	return tierFor(entry.Tiers, quantity)
+}
+
+func (entry *Entry) Variant(sku string) *VariantEntry {
+	for _, variant := range entry.Variants {
+		if variant.SKU != nil && *variant.SKU == sku {
+			return variant
+		}
+	}
+
+	return nil
+}
+
+func (variant *VariantEntry) TierFor(quantity int64) *Tier {
+	return tierFor(variant.Tiers, quantity)
+}
+
+func tierFor(tiers []*Tier, quantity int64) *Tier {
+	var result *Tier
+
+	for _, tier := range tiers {
+		if tier.MinQuantity <= quantity && (result == nil || tier.MinQuantity > result.MinQuantity) {
+			result = tier
+		}
+	}
+
+	return result
+}
+
+var validator = validation.New(func(value interface{}) validation.Errors {
+	list := value.(*PriceList)
+	errs := validation.NotBlank("name", list.Name)
+
+	if !list.Currency.Valid() {
+		errs = append(errs, validation.NewFieldError("currency", "iso4217", "must be an ISO 4217 code"))
+	}
+
+	return errs
+})
+
+// Validate returns validation.Errors when the price list is not valid
+func Validate(list *PriceList) error {
+	return validator.Validate(list)
+}
+
+// ValidateEntry returns validation.Errors when the entry is not valid for the list,
+// it needs a price, some tiers or some variants, every price is in the currency of
+// the list, the minimum quantities of the tiers must be positive and can not repeat,
+// the same rules apply to each variant, which can not repeat its SKU either
+func ValidateEntry(list *PriceList, entry *Entry) error {
+	errs := validation.NotBlank("productId", entry.ProductID)
+
+	if entry.ProductID == nil {
+		errs = append(errs, validation.NewFieldError("productId", "required", "is required"))
+	}
+
+	check := func(path string, price *money.Money) {
+		if price == nil {
+			errs = append(errs, validation.NewFieldError(path, "required", "is required"))
+
+			return
+		}
+
+		if price.IsNegative() {
+			errs = append(errs, validation.NewFieldError(path, "gte", "must be greater than or equal to 0"))
+		}
+
+		if price.Currency != list.Currency {
+			errs = append(errs, validation.NewFieldError(path+".currency", "eq", "must be the currency of the price list"))
+		}
+	}
+
+	checkTiers := func(path string, tiers []*Tier) {
+		quantities := map[int64]bool{}
+
+		for index, tier := range tiers {
+			tierPath := fmt.Sprintf("%stiers[%d]", path, index)
+			check(tierPath+".price", tier.Price)
+
+			if tier.MinQuantity < 1 {
+				errs = append(errs, validation.NewFieldError(tierPath+".minQuantity", "gte", "must be greater than or equal to 1"))
+			}
+
+			if quantities[tier.MinQuantity] {
+				errs = append(errs, validation.NewFieldError(tierPath+".minQuantity", "unique", "is repeated"))
+			}
+
+			quantities[tier.MinQuantity] = true
+		}
+	}
+
+	if entry.Price == nil && len(entry.Tiers) == 0 && len(entry.Variants) == 0 {
+		errs = append(errs, validation.NewFieldError("price", "required_without", "is required when there are no tiers or variants"))
+	}
+
+	if entry.Price != nil {
+		check("price", entry.Price)
+	}
+
+	checkTiers("", entry.Tiers)
+
+	skus := map[string]bool{}
+
+	for index, variant := range entry.Variants {
+		path := fmt.Sprintf("variants[%d].", index)
+		errs = append(errs, validation.NotBlank(path+"sku", variant.SKU)...)
+
+		if variant.SKU == nil {
+			errs = append(errs, validation.NewFieldError(path+"sku", "required", "is required"))
+		} else if skus[*variant.SKU] {
+			errs = append(errs, validation.NewFieldError(path+"sku", "unique", "is repeated"))
+		} else {
+			skus[*variant.SKU] = true
+		}
+
+		if variant.Price == nil && len(variant.Tiers) == 0 {
+			errs = append(errs, validation.NewFieldError(path+"price", "required_without", "is required when there are no tiers"))
+		}
+
+		if variant.Price != nil {
+			check(path+"price", variant.Price)
+		}
+
+		checkTiers(path, variant.Tiers)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+type PriceListRepository interface {
+	Store(*PriceList) error
+	Update(ID *string, list *PriceList) error
+	Find(ID *string) (*PriceList, error)
+	Remove(ID *string) error
+	All() ([]*PriceList, error)
+}
+
+// EntryRepository keeps the entries apart from their lists, a
+// list can override the price of any number of products
+type EntryRepository interface {
+	Store(*Entry) error
+	Find(priceListID, productID *string) (*Entry, error)
+	FindByPriceListID(priceListID *string) ([]*Entry, error)
+	Remove(priceListID, productID *string) error
+}
+
+// NotFoundError is returned when the requested price list does not exist
+type NotFoundError struct {
+	ID string
+}
+
+func (err NotFoundError) Error() string {
+	return fmt.Sprintf("the price list \"%s\" does not exist", err.ID)
+}
+
+// NoPriceError is returned when the product does not have a price in the currency
+type NoPriceError struct {
+	ProductID string
+	Currency  money.Currency
+}
+
+func (err NoPriceError) Error() string {
+	if err.Currency == "" {
+		return fmt.Sprintf("the product \"%s\" does not have a price", err.ProductID)
+	}
+
+	return fmt.Sprintf("the product \"%s\" does not have a price in %s", err.ProductID, err.Currency)
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"github.com/alejo-lapix/products-go/pkg/dynamo"
+	"github.com/alejo-lapix/products-go/pkg/pricelists"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+type DynamoDBPriceListRepository struct {
+	DynamoDB  *dynamodb.DynamoDB
+	tableName *string
+}
+
+func NewDynamoDBPriceListRepository(db *dynamodb.DynamoDB) *DynamoDBPriceListRepository {
+	return &DynamoDBPriceListRepository{
+		DynamoDB:  db,
+		tableName: aws.String("price-lists"),
+	}
+}
+
+func (repository *DynamoDBPriceListRepository) Store(list *pricelists.PriceList) error {
+	item, err := dynamodbattribute.MarshalMap(list)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+		Item:                item,
+		TableName:           repository.tableName,
+	})
+
+	return err
+}
+
+func (repository *DynamoDBPriceListRepository) Update(ID *string, list *pricelists.PriceList) error {
+	item, err := dynamodbattribute.MarshalMap(list)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+		ConditionExpression:       aws.String("id = :id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":id": {S: ID}},
+		Item:                      item,
+		TableName:                 repository.tableName,
+	})
+
+	return err
+}
+
+func (repository *DynamoDBPriceListRepository) Find(ID *string) (*pricelists.PriceList, error) {
+	output, err := repository.DynamoDB.GetItem(&dynamodb.GetItemInput{
+		Key:       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+		TableName: repository.tableName,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	list := &pricelists.PriceList{}
+
+	if err = dynamodbattribute.UnmarshalMap(output.Item, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (repository *DynamoDBPriceListRepository) Remove(ID *string) error {
+	_, err := repository.DynamoDB.DeleteItem(&dynamodb.DeleteItemInput{
+		Key:       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+		TableName: repository.tableName,
+	})
+
+	return err
+}
+
+func (repository *DynamoDBPriceListRepository) All() ([]*pricelists.PriceList, error) {
+	items := make([]*pricelists.PriceList, 0)
+	output, err := repository.DynamoDB.Scan(&dynamodb.ScanInput{TableName: repository.tableName})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = dynamodbattribute.UnmarshalListOfMaps(output.Items, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// DynamoDBEntryRepository uses priceListId as the partition key and productId as the sort key
+type DynamoDBEntryRepository struct {
+	DynamoDB  *dynamodb.DynamoDB
+	tableName *string
+}
+
+func NewDynamoDBEntryRepository(db *dynamodb.DynamoDB) *DynamoDBEntryRepository {
+	return &DynamoDBEntryRepository{
+		DynamoDB:  db,
+		tableName: aws.String("price-list-entries"),
+	}
+}
+
+func entryKey(priceListID, productID *string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"priceListId": {S: priceListID},
+		"productId":   {S: productID},
+	}
+}
+
+func (repository *DynamoDBEntryRepository) Store(entry *pricelists.Entry) error {
+	item, err := dynamodbattribute.MarshalMap(entry)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+		Item:      item,
+		TableName: repository.tableName,
+	})
+
+	return err
+}
+
+func (repository *DynamoDBEntryRepository) Find(priceListID, productID *string) (*pricelists.Entry, error) {
+	output, err := repository.DynamoDB.GetItem(&dynamodb.GetItemInput{
+		Key:       entryKey(priceListID, productID),
+		TableName: repository.tableName,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	entry := &pricelists.Entry{}
+
+	if err = dynamodbattribute.UnmarshalMap(output.Item, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (repository *DynamoDBEntryRepository) FindByPriceListID(priceListID *string) ([]*pricelists.Entry, error) {
+	items := make([]*pricelists.Entry, 0)
+	err := dynamo.Query(repository.DynamoDB, &dynamodb.QueryInput{
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":priceListId": {S: priceListID}},
+		KeyConditionExpression:    aws.String("priceListId = :priceListId"),
+		TableName:                 repository.tableName,
+	}, 0, &items)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (repository *DynamoDBEntryRepository) Remove(priceListID, productID *string) error {
+	_, err := repository.DynamoDB.DeleteItem(&dynamodb.DeleteItemInput{
+		Key:       entryKey(priceListID, productID),
+		TableName: repository.tableName,
+	})
+
+	return err
+}
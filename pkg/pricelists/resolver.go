@@ -0,0 +1,186 @@
+package pricelists
+
+import (
+	"fmt"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/products"
+)
+
+type Source string
+
+const (
+	// BasePrice comes from the product itself
+	BasePrice Source = "base"
+	// ListPrice comes from the entry of the price list
+	ListPrice Source = "priceList"
+	// TierPrice comes from a quantity tier of the entry
+	TierPrice Source = "tier"
+)
+
+// Resolution is the unit price that applies to a quantity of a product and where it came from
+type Resolution struct {
+	Unit        *money.Money `json:"unit"`
+	Total       *money.Money `json:"total"`
+	Source      Source       `json:"source"`
+	PriceListID *string      `json:"priceListId,omitempty"`
+	SKU         *string      `json:"sku,omitempty"`
+	MinQuantity int64        `json:"minQuantity,omitempty"`
+}
+
+// Resolver converts the base price with Pricing when the product does not have a price
+// in the currency of the list, without Pricing those products fail with NoPriceError
+type Resolver struct {
+	Lists   PriceListRepository
+	Entries EntryRepository
+	Pricing *products.PricingService
+}
+
+func NewResolver(lists PriceListRepository, entries EntryRepository) *Resolver {
+	return &Resolver{Lists: lists, Entries: entries}
+}
+
+// Resolve picks the price of the quantity of the product in the list, the tier reached
+// by the quantity goes first, then the price of the entry and at last the base price
+// of the product in the currency of the list, an empty list uses the base price
+func (resolver *Resolver) Resolve(product *products.Product, priceListID *string, quantity int64) (*Resolution, error) {
+	return resolver.resolve(product, nil, priceListID, quantity)
+}
+
+// ResolveVariant picks the price of the variant like Resolve does for the product, the
+// entry of the variant goes first, the entry of the product only applies when the variant
+// shares the price of the product, and the base price is the price of the variant
+func (resolver *Resolver) ResolveVariant(product *products.Product, sku, priceListID *string, quantity int64) (*Resolution, error) {
+	variant := product.Variant(*sku)
+
+	if variant == nil {
+		return nil, fmt.Errorf("the product \"%s\" does not have the variant \"%s\"", *product.ID, *sku)
+	}
+
+	result, err := resolver.resolve(product, variant, priceListID, quantity)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.SKU = variant.SKU
+
+	return result, nil
+}
+
+func (resolver *Resolver) resolve(product *products.Product, variant *products.Variant, priceListID *string, quantity int64) (*Resolution, error) {
+	if quantity < 1 {
+		return nil, fmt.Errorf("the quantity must be greater than 0, %d given", quantity)
+	}
+
+	if priceListID == nil || *priceListID == "" {
+		price := product.Price
+
+		if variant != nil {
+			price = product.PriceOf(variant)
+		}
+
+		if price == nil {
+			return nil, NoPriceError{ProductID: *product.ID}
+		}
+
+		return resolution(price, quantity, BasePrice), nil
+	}
+
+	list, err := resolver.Lists.Find(priceListID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if list == nil {
+		return nil, NotFoundError{ID: *priceListID}
+	}
+
+	entry, err := resolver.Entries.Find(priceListID, product.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := override(entry, variant, quantity)
+
+	if result == nil {
+		price, err := resolver.base(product, variant, list.Currency)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = resolution(price, quantity, BasePrice)
+	}
+
+	result.PriceListID = list.ID
+
+	return result, nil
+}
+
+// override returns the price of the entry for the quantity, nil when the entry does
+// not override it, the tier reached by the quantity goes before the price
+func override(entry *Entry, variant *products.Variant, quantity int64) *Resolution {
+	if entry == nil {
+		return nil
+	}
+
+	price, tiers := entry.Price, entry.Tiers
+
+	if variant != nil {
+		if own := entry.Variant(*variant.SKU); own != nil {
+			price, tiers = own.Price, own.Tiers
+		} else if variant.Price != nil {
+			return nil
+		}
+	}
+
+	if tier := tierFor(tiers, quantity); tier != nil {
+		result := resolution(tier.Price, quantity, TierPrice)
+		result.MinQuantity = tier.MinQuantity
+
+		return result
+	}
+
+	if price != nil {
+		return resolution(price, quantity, ListPrice)
+	}
+
+	return nil
+}
+
+// base returns the price of the product, or of the variant when it has its own, in the
+// currency of the list, it is converted from the main price when that price is missing
+func (resolver *Resolver) base(product *products.Product, variant *products.Variant, currency money.Currency) (*money.Money, error) {
+	if variant != nil && variant.Price != nil {
+		if variant.Price.Currency == currency {
+			return variant.Price, nil
+		}
+
+		if resolver.Pricing == nil {
+			return nil, NoPriceError{ProductID: *product.ID, Currency: currency}
+		}
+
+		return resolver.Pricing.Convert(variant.Price, currency)
+	}
+
+	if price := product.PriceIn(currency); price != nil {
+		return price, nil
+	}
+
+	if resolver.Pricing == nil || product.Price == nil {
+		return nil, NoPriceError{ProductID: *product.ID, Currency: currency}
+	}
+
+	return resolver.Pricing.PriceIn(product, currency)
+}
+
+func resolution(unit *money.Money, quantity int64, source Source) *Resolution {
+	return &Resolution{
+		Unit:   unit,
+		Total:  money.New(unit.Amount*quantity, unit.Currency),
+		Source: source,
+	}
+}
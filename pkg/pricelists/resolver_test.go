@@ -0,0 +1,165 @@
+package pricelists
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/products"
+	"github.com/alejo-lapix/products-go/pkg/rates"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	wholesale := &PriceList{ID: s("wholesale"), Name: s("Wholesale"), Kind: CustomerGroup, Currency: "USD"}
+	marketplace := &PriceList{ID: s("marketplace"), Name: s("Marketplace"), Kind: Channel, Currency: "EUR"}
+	resolver := NewResolver(newMemoryLists(wholesale, marketplace), newMemoryEntries(
+		&Entry{PriceListID: s("wholesale"), ProductID: s("p1"), Price: money.New(900, "USD"), Tiers: []*Tier{
+			{MinQuantity: 100, Price: money.New(700, "USD")},
+			{MinQuantity: 10, Price: money.New(800, "USD")},
+		}},
+		&Entry{PriceListID: s("wholesale"), ProductID: s("p2"), Tiers: []*Tier{{MinQuantity: 10, Price: money.New(100, "USD")}}},
+	))
+	p1 := &products.Product{ID: s("p1"), Price: money.New(1000, "USD"), Prices: money.Prices{money.New(950, "EUR")}}
+	p2 := &products.Product{ID: s("p2"), Price: money.New(200, "USD")}
+
+	tests := []struct {
+		name        string
+		product     *products.Product
+		priceListID *string
+		quantity    int64
+		want        *money.Money
+		source      Source
+		wantErr     bool
+	}{
+		{name: "Without list", product: p1, quantity: 2, want: money.New(1000, "USD"), source: BasePrice},
+		{name: "Entry price", product: p1, priceListID: s("wholesale"), quantity: 9, want: money.New(900, "USD"), source: ListPrice},
+		{name: "Lowest tier", product: p1, priceListID: s("wholesale"), quantity: 10, want: money.New(800, "USD"), source: TierPrice},
+		{name: "Highest tier", product: p1, priceListID: s("wholesale"), quantity: 250, want: money.New(700, "USD"), source: TierPrice},
+		{name: "Tiers only below the minimum", product: p2, priceListID: s("wholesale"), quantity: 1, want: money.New(200, "USD"), source: BasePrice},
+		{name: "Base price in the currency of the list", product: p1, priceListID: s("marketplace"), quantity: 1, want: money.New(950, "EUR"), source: BasePrice},
+		{name: "Base price without the currency of the list", product: p2, priceListID: s("marketplace"), quantity: 1, wantErr: true},
+		{name: "Product without price", product: &products.Product{ID: s("p3")}, quantity: 1, wantErr: true},
+		{name: "Unknown list", product: p1, priceListID: s("unknown"), quantity: 1, wantErr: true},
+		{name: "Zero quantity", product: p1, quantity: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.Resolve(tt.product, tt.priceListID, tt.quantity)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if *got.Unit != *tt.want || got.Source != tt.source {
+				t.Errorf("Resolve() = %v from %s, want %v from %s", got.Unit, got.Source, tt.want, tt.source)
+			}
+
+			if got.Total.Amount != tt.want.Amount*tt.quantity {
+				t.Errorf("Resolve() total = %v, want %d", got.Total, tt.want.Amount*tt.quantity)
+			}
+		})
+	}
+}
+
+func TestResolver_ResolveConverted(t *testing.T) {
+	marketplace := &PriceList{ID: s("marketplace"), Name: s("Marketplace"), Kind: Channel, Currency: "EUR"}
+	resolver := NewResolver(newMemoryLists(marketplace), newMemoryEntries())
+	resolver.Pricing = products.NewPricingService(&rates.Table{Base: "USD", Rates: map[money.Currency]float64{"EUR": 0.5}}, time.Hour)
+
+	got, err := resolver.Resolve(&products.Product{ID: s("p2"), Price: money.New(200, "USD")}, s("marketplace"), 3)
+
+	if err != nil || *got.Unit != *money.New(100, "EUR") || got.Total.Amount != 300 || got.Source != BasePrice {
+		t.Errorf("Resolve() = %+v, error = %v, want 1.00 EUR converted from USD", got, err)
+	}
+}
+
+func TestResolver_ResolveVariant(t *testing.T) {
+	wholesale := &PriceList{ID: s("wholesale"), Name: s("Wholesale"), Kind: CustomerGroup, Currency: "USD"}
+	marketplace := &PriceList{ID: s("marketplace"), Name: s("Marketplace"), Kind: Channel, Currency: "EUR"}
+	resolver := NewResolver(newMemoryLists(wholesale, marketplace), newMemoryEntries(
+		&Entry{PriceListID: s("wholesale"), ProductID: s("p1"), Price: money.New(900, "USD"), Variants: []*VariantEntry{
+			{SKU: s("p1-xl"), Price: money.New(1100, "USD"), Tiers: []*Tier{{MinQuantity: 10, Price: money.New(1000, "USD")}}},
+		}},
+	))
+	resolver.Pricing = products.NewPricingService(&rates.Table{Base: "USD", Rates: map[money.Currency]float64{"EUR": 0.5}}, time.Hour)
+	p1 := &products.Product{ID: s("p1"), Price: money.New(1000, "USD"), Variants: []*products.Variant{
+		{SKU: s("p1-s")},
+		{SKU: s("p1-xl"), Price: money.New(1200, "USD")},
+		{SKU: s("p1-xxl"), Price: money.New(1400, "USD")},
+	}}
+
+	tests := []struct {
+		name        string
+		sku         *string
+		priceListID *string
+		quantity    int64
+		want        *money.Money
+		source      Source
+		wantErr     bool
+	}{
+		{name: "Variant price without list", sku: s("p1-xl"), quantity: 1, want: money.New(1200, "USD"), source: BasePrice},
+		{name: "Product price without list", sku: s("p1-s"), quantity: 1, want: money.New(1000, "USD"), source: BasePrice},
+		{name: "Variant entry price", sku: s("p1-xl"), priceListID: s("wholesale"), quantity: 1, want: money.New(1100, "USD"), source: ListPrice},
+		{name: "Variant entry tier", sku: s("p1-xl"), priceListID: s("wholesale"), quantity: 10, want: money.New(1000, "USD"), source: TierPrice},
+		{name: "Product entry for the variants sharing its price", sku: s("p1-s"), priceListID: s("wholesale"), quantity: 1, want: money.New(900, "USD"), source: ListPrice},
+		{name: "Variant price over the product entry", sku: s("p1-xxl"), priceListID: s("wholesale"), quantity: 1, want: money.New(1400, "USD"), source: BasePrice},
+		{name: "Variant price converted", sku: s("p1-xxl"), priceListID: s("marketplace"), quantity: 1, want: money.New(700, "EUR"), source: BasePrice},
+		{name: "Unknown variant", sku: s("p1-m"), quantity: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.ResolveVariant(p1, tt.sku, tt.priceListID, tt.quantity)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveVariant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if *got.Unit != *tt.want || got.Source != tt.source || *got.SKU != *tt.sku {
+				t.Errorf("ResolveVariant() = %v from %s for %s, want %v from %s", got.Unit, got.Source, *got.SKU, tt.want, tt.source)
+			}
+		})
+	}
+}
+
+func TestValidateEntry(t *testing.T) {
+	list := &PriceList{ID: s("wholesale"), Currency: "USD"}
+	tests := []struct {
+		name    string
+		entry   *Entry
+		wantErr bool
+	}{
+		{name: "Price", entry: &Entry{ProductID: s("p1"), Price: money.New(100, "USD")}},
+		{name: "Tiers", entry: &Entry{ProductID: s("p1"), Tiers: []*Tier{{MinQuantity: 5, Price: money.New(90, "USD")}}}},
+		{name: "Without prices", entry: &Entry{ProductID: s("p1")}, wantErr: true},
+		{name: "Without product", entry: &Entry{Price: money.New(100, "USD")}, wantErr: true},
+		{name: "Other currency", entry: &Entry{ProductID: s("p1"), Price: money.New(100, "EUR")}, wantErr: true},
+		{name: "Repeated tiers", entry: &Entry{ProductID: s("p1"), Tiers: []*Tier{
+			{MinQuantity: 5, Price: money.New(90, "USD")},
+			{MinQuantity: 5, Price: money.New(80, "USD")},
+		}}, wantErr: true},
+		{name: "Variants only", entry: &Entry{ProductID: s("p1"), Variants: []*VariantEntry{{SKU: s("p1-xl"), Price: money.New(100, "USD")}}}},
+		{name: "Variant without prices", entry: &Entry{ProductID: s("p1"), Variants: []*VariantEntry{{SKU: s("p1-xl")}}}, wantErr: true},
+		{name: "Variant without SKU", entry: &Entry{ProductID: s("p1"), Variants: []*VariantEntry{{Price: money.New(100, "USD")}}}, wantErr: true},
+		{name: "Repeated variants", entry: &Entry{ProductID: s("p1"), Variants: []*VariantEntry{
+			{SKU: s("p1-xl"), Price: money.New(100, "USD")},
+			{SKU: s("p1-xl"), Price: money.New(90, "USD")},
+		}}, wantErr: true},
+		{name: "Variant in other currency", entry: &Entry{ProductID: s("p1"), Variants: []*VariantEntry{{SKU: s("p1-xl"), Tiers: []*Tier{{MinQuantity: 5, Price: money.New(90, "EUR")}}}}}, wantErr: true},
+		{name: "Zero quantity tier", entry: &Entry{ProductID: s("p1"), Tiers: []*Tier{{MinQuantity: 0, Price: money.New(90, "USD")}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateEntry(list, tt.entry); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
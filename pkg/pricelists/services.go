@@ -0,0 +1,151 @@
+package pricelists
+
+import (
+	"fmt"
+
+	"github.com/alejo-lapix/products-go/pkg/ids"
+	"github.com/alejo-lapix/products-go/pkg/products"
+	"github.com/alejo-lapix/products-go/pkg/validation"
+)
+
+type PriceListService struct {
+	Lists    PriceListRepository
+	Entries  EntryRepository
+	Products products.ProductRepository
+	IDs      ids.Generator
+}
+
+func NewPriceListService(lists PriceListRepository, entries EntryRepository, productRepository products.ProductRepository) *PriceListService {
+	return &PriceListService{
+		Lists:    lists,
+		Entries:  entries,
+		Products: productRepository,
+		IDs:      ids.UUIDGenerator{},
+	}
+}
+
+// NewPriceList gives a new ID to the list before storing it
+func (service *PriceListService) NewPriceList(list *PriceList) (*PriceList, error) {
+	id := service.IDs.NewID()
+	list.ID = &id
+
+	if err := Validate(list); err != nil {
+		return nil, err
+	}
+
+	if err := service.Lists.Store(list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (service *PriceListService) FindPriceList(ID *string) (*PriceList, error) {
+	list, err := service.Lists.Find(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if list == nil {
+		return nil, NotFoundError{ID: *ID}
+	}
+
+	return list, nil
+}
+
+// UpdatePriceList keeps the currency, the entries are priced in it
+func (service *PriceListService) UpdatePriceList(ID *string, list *PriceList) (*PriceList, error) {
+	current, err := service.FindPriceList(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	list.ID = current.ID
+	list.Currency = current.Currency
+
+	if err = Validate(list); err != nil {
+		return nil, err
+	}
+
+	if err = service.Lists.Update(ID, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// RemovePriceList removes the entries before the list
+func (service *PriceListService) RemovePriceList(ID *string) error {
+	if _, err := service.FindPriceList(ID); err != nil {
+		return err
+	}
+
+	entries, err := service.Entries.FindByPriceListID(ID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err = service.Entries.Remove(ID, entry.ProductID); err != nil {
+			return err
+		}
+	}
+
+	return service.Lists.Remove(ID)
+}
+
+// SetEntry replaces the price of the product in the list, the
+// SKUs of the variants of the entry must belong to the product
+func (service *PriceListService) SetEntry(priceListID *string, entry *Entry) (*Entry, error) {
+	list, err := service.FindPriceList(priceListID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entry.PriceListID = list.ID
+
+	if err = ValidateEntry(list, entry); err != nil {
+		return nil, err
+	}
+
+	product, err := service.Products.FindOne(entry.ProductID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, products.NotFoundError{ID: *entry.ProductID}
+	}
+
+	errs := validation.Errors{}
+
+	for index, variant := range entry.Variants {
+		if product.Variant(*variant.SKU) == nil {
+			errs = append(errs, validation.NewFieldError(fmt.Sprintf("variants[%d].sku", index), "exists", "is not a variant of the product"))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if err = service.Entries.Store(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// RemoveEntry takes the product back to its base price in the list
+func (service *PriceListService) RemoveEntry(priceListID, productID *string) error {
+	if _, err := service.FindPriceList(priceListID); err != nil {
+		return err
+	}
+
+	return service.Entries.Remove(priceListID, productID)
+}
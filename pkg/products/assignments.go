@@ -0,0 +1,51 @@
+package products
+
+// CategoryAssignments exposes the products of a category to the
+// category services, it satisfies categories.CategoryProducts
+type CategoryAssignments struct {
+	Repository ProductRepository
+}
+
+func NewCategoryAssignments(repository ProductRepository) *CategoryAssignments {
+	return &CategoryAssignments{Repository: repository}
+}
+
+func (assignments *CategoryAssignments) ProductIDs(categoryID *string) ([]*string, error) {
+	items, err := assignments.Repository.FindByCategoryID(categoryID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]*string, len(items))
+
+	for index, item := range items {
+		ids[index] = item.ID
+	}
+
+	return ids, nil
+}
+
+func (assignments *CategoryAssignments) MoveProducts(productIDs []*string, categoryID *string) error {
+	for _, id := range productIDs {
+		product, err := assignments.Repository.FindOne(id)
+
+		if err != nil {
+			return err
+		}
+
+		if product == nil {
+			continue
+		}
+
+		product.CategoryID = categoryID
+		// The position belongs to the previous category
+		product.Position = nil
+
+		if err = assignments.Repository.Update(product.ID, product); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
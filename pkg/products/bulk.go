@@ -0,0 +1,361 @@
+package products
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/money"
+)
+
+type AdjustmentKind string
+
+const (
+	// PercentageAdjustment changes every price by a percentage, -10 lowers them a 10%
+	PercentageAdjustment AdjustmentKind = "percentage"
+	// AmountAdjustment adds an amount, only to the prices of the same currency
+	AmountAdjustment AdjustmentKind = "amount"
+)
+
+// Adjustment changes the prices of the products, the results are rounded with
+// Rounding, or with the policy of the currency when it is nil, and then moved to
+// the Ending of the currency, the prices never go below zero
+type Adjustment struct {
+	Kind       AdjustmentKind            `json:"kind"`
+	Percentage float64                   `json:"percentage,omitempty"`
+	Amount     *money.Money              `json:"amount,omitempty"`
+	Rounding   *money.RoundingPolicy     `json:"rounding,omitempty"`
+	Endings    map[money.Currency]Ending `json:"endings,omitempty"`
+}
+
+func (adjustment Adjustment) check() error {
+	switch adjustment.Kind {
+	case PercentageAdjustment:
+		if adjustment.Percentage <= -100 {
+			return fmt.Errorf("the percentage must be greater than -100, %v given", adjustment.Percentage)
+		}
+	case AmountAdjustment:
+		if adjustment.Amount == nil || !adjustment.Amount.Currency.Valid() {
+			return fmt.Errorf("the amount of the adjustment is required")
+		}
+	default:
+		return fmt.Errorf("unknown adjustment \"%s\"", adjustment.Kind)
+	}
+
+	return nil
+}
+
+// Apply returns the adjusted price, the same price when it does not apply to its currency
+func (adjustment Adjustment) Apply(price *money.Money) *money.Money {
+	var minor float64
+
+	switch adjustment.Kind {
+	case PercentageAdjustment:
+		minor = float64(price.Amount) * (1 + adjustment.Percentage/100)
+	case AmountAdjustment:
+		if adjustment.Amount.Currency != price.Currency {
+			return price
+		}
+
+		minor = float64(price.Amount + adjustment.Amount.Amount)
+	default:
+		return price
+	}
+
+	policy := money.RoundingFor(price.Currency)
+
+	if adjustment.Rounding != nil {
+		policy = *adjustment.Rounding
+	}
+
+	result := money.New(policy.Round(minor), price.Currency)
+
+	if ending, ok := adjustment.Endings[price.Currency]; ok {
+		result = ending.Apply(result)
+	}
+
+	if result.IsNegative() {
+		result = money.New(0, price.Currency)
+	}
+
+	return result
+}
+
+// Selection picks the products of a bulk operation, by their IDs or by their category,
+// Recursive includes the products of all the subcategories, when only the Filter is
+// given it runs over all the products, otherwise it narrows the other criteria
+type Selection struct {
+	IDs        []*string
+	CategoryID *string
+	Recursive  bool
+	Filter     func(product *Product) bool
+}
+
+const defaultBulkBatchSize = 25
+
+type BulkPriceOptions struct {
+	// DryRun only reports the new prices, nothing is written
+	DryRun bool
+	// BatchSize is the amount of products updated on every step
+	BatchSize int
+	// Pause is the time waited between the batches, so the writes are throttled
+	Pause time.Duration
+	// Progress is called after every batch of products
+	Progress func(progress *BulkPriceProgress)
+}
+
+type BulkPriceProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// AdjustedPrice is the preview of a price, SKU is nil for the prices of the product itself
+type AdjustedPrice struct {
+	ProductID *string      `json:"productId"`
+	SKU       *string      `json:"sku,omitempty"`
+	Previous  *money.Money `json:"previous"`
+	Next      *money.Money `json:"next"`
+}
+
+type BulkPriceReport struct {
+	DryRun bool             `json:"dryRun"`
+	Prices []*AdjustedPrice `json:"prices"`
+	// Updated has the products written, in a dry run they are the ones that would change
+	Updated []*string `json:"updated"`
+}
+
+// BulkPriceService adjusts the prices of many products at once,
+// the archived products are left as they are
+type BulkPriceService struct {
+	Repository ProductRepository
+	Categories categories.CategoryRepository
+	sleep      func(duration time.Duration)
+}
+
+func NewBulkPriceService(repository ProductRepository, categoryRepository categories.CategoryRepository) *BulkPriceService {
+	return &BulkPriceService{
+		Repository: repository,
+		Categories: categoryRepository,
+		sleep:      time.Sleep,
+	}
+}
+
+// Adjust previews the new prices of all the selected products, then every batch is read
+// again right before writing it, so the edits made during the pauses are kept, when it
+// fails the report only has the products already written, an interrupted run is not
+// resumed, run it again only for the missing products
+func (service *BulkPriceService) Adjust(selection *Selection, adjustment Adjustment, options *BulkPriceOptions) (*BulkPriceReport, error) {
+	if options == nil {
+		options = &BulkPriceOptions{}
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultBulkBatchSize
+	}
+
+	if err := adjustment.check(); err != nil {
+		return nil, err
+	}
+
+	items, err := service.selected(selection)
+
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BulkPriceReport{DryRun: options.DryRun, Prices: []*AdjustedPrice{}, Updated: []*string{}}
+	changed := make([]*string, 0)
+
+	for _, product := range items {
+		if _, prices := adjusted(product, adjustment); len(prices) > 0 {
+			if options.DryRun {
+				report.Prices = append(report.Prices, prices...)
+				report.Updated = append(report.Updated, product.ID)
+			}
+
+			changed = append(changed, product.ID)
+		}
+	}
+
+	if options.DryRun {
+		return report, nil
+	}
+
+	return report, service.write(changed, adjustment, options, report)
+}
+
+// write adjusts the current version of every batch of products, the products
+// archived or deleted since the preview are skipped
+func (service *BulkPriceService) write(ids []*string, adjustment Adjustment, options *BulkPriceOptions, report *BulkPriceReport) error {
+	for start := 0; start < len(ids); start += options.BatchSize {
+		if start > 0 && options.Pause > 0 {
+			service.wait(options.Pause)
+		}
+
+		end := start + options.BatchSize
+
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		items, err := service.Repository.FindMany(ids[start:end])
+
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			product, prices := adjusted(item, adjustment)
+
+			if product == nil {
+				continue
+			}
+
+			if err := service.Repository.Update(product.ID, product); err != nil {
+				return err
+			}
+
+			report.Prices = append(report.Prices, prices...)
+			report.Updated = append(report.Updated, product.ID)
+		}
+
+		if options.Progress != nil {
+			options.Progress(&BulkPriceProgress{Done: end, Total: len(ids)})
+		}
+	}
+
+	return nil
+}
+
+func (service *BulkPriceService) wait(duration time.Duration) {
+	if service.sleep == nil {
+		time.Sleep(duration)
+
+		return
+	}
+
+	service.sleep(duration)
+}
+
+func (service *BulkPriceService) selected(selection *Selection) ([]*Product, error) {
+	var items []*Product
+	var err error
+
+	switch {
+	case len(selection.IDs) > 0:
+		items, err = service.Repository.FindMany(selection.IDs)
+	case selection.CategoryID != nil:
+		items, err = service.inCategory(selection.CategoryID, selection.Recursive)
+	case selection.Filter != nil:
+		items, err = service.Repository.All()
+	default:
+		return nil, fmt.Errorf("the selection needs some IDs, a category or a filter")
+	}
+
+	if err != nil || selection.Filter == nil {
+		return items, err
+	}
+
+	result := make([]*Product, 0, len(items))
+
+	for _, item := range items {
+		if selection.Filter(item) {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (service *BulkPriceService) inCategory(categoryID *string, recursive bool) ([]*Product, error) {
+	ids := []*string{categoryID}
+
+	if recursive {
+		descendants, err := categories.Descendants(service.Categories, categoryID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, descendant := range descendants {
+			ids = append(ids, descendant.ID)
+		}
+	}
+
+	result := make([]*Product, 0)
+
+	for _, id := range ids {
+		items, err := service.Repository.FindByCategoryID(id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, items...)
+	}
+
+	return result, nil
+}
+
+// withOwnPrices copies the product and the variants, so a dry
+// run never touches the products shared by the repository
+func withOwnPrices(product *Product) *Product {
+	result := *product
+	result.Prices = append(money.Prices{}, product.Prices...)
+	result.Variants = make([]*Variant, len(product.Variants))
+
+	for index, variant := range product.Variants {
+		copied := *variant
+		result.Variants[index] = &copied
+	}
+
+	return &result
+}
+
+// adjusted returns a copy of the product with the new prices and the prices
+// that changed, nil when the product is archived or none of its prices change
+func adjusted(product *Product, adjustment Adjustment) (*Product, []*AdjustedPrice) {
+	if product.IsArchived() {
+		return nil, nil
+	}
+
+	result := withOwnPrices(product)
+	prices := adjust(result, adjustment)
+
+	if len(prices) == 0 {
+		return nil, nil
+	}
+
+	return result, prices
+}
+
+// adjust replaces the prices of the product and of its variants,
+// it returns the ones that changed
+func adjust(product *Product, adjustment Adjustment) []*AdjustedPrice {
+	result := make([]*AdjustedPrice, 0)
+	replace := func(sku *string, price *money.Money) *money.Money {
+		if price == nil {
+			return nil
+		}
+
+		next := adjustment.Apply(price)
+
+		if *next != *price {
+			result = append(result, &AdjustedPrice{ProductID: product.ID, SKU: sku, Previous: price, Next: next})
+		}
+
+		return next
+	}
+
+	product.Price = replace(nil, product.Price)
+
+	for index, price := range product.Prices {
+		product.Prices[index] = replace(nil, price)
+	}
+
+	for _, variant := range product.Variants {
+		variant.Price = replace(variant.SKU, variant.Price)
+	}
+
+	return result
+}
@@ -0,0 +1,186 @@
+package products
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/money"
+)
+
+func TestAdjustment_Apply(t *testing.T) {
+	tests := []struct {
+		name       string
+		adjustment Adjustment
+		price      *money.Money
+		want       *money.Money
+	}{
+		{name: "Raises a percentage", adjustment: Adjustment{Kind: PercentageAdjustment, Percentage: 5}, price: m(10.10), want: m(10.61)},
+		{name: "Lowers a percentage", adjustment: Adjustment{Kind: PercentageAdjustment, Percentage: -10}, price: m(10), want: m(9)},
+		{
+			name:       "Rounds with the given policy",
+			adjustment: Adjustment{Kind: PercentageAdjustment, Percentage: 5, Rounding: &money.RoundingPolicy{Mode: money.Up, Increment: 50}},
+			price:      m(10.10),
+			want:       m(11),
+		},
+		{
+			name:       "Moves to the ending of the currency",
+			adjustment: Adjustment{Kind: PercentageAdjustment, Percentage: 5, Endings: map[money.Currency]Ending{"USD": {Step: 100, Value: 99}}},
+			price:      m(10.10),
+			want:       m(10.99),
+		},
+		{name: "Adds an amount", adjustment: Adjustment{Kind: AmountAdjustment, Amount: m(1.5)}, price: m(10), want: m(11.5)},
+		{name: "Never goes below zero", adjustment: Adjustment{Kind: AmountAdjustment, Amount: m(-20)}, price: m(10), want: m(0)},
+		{name: "Skips other currencies", adjustment: Adjustment{Kind: AmountAdjustment, Amount: m(1)}, price: money.New(900, "EUR"), want: money.New(900, "EUR")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.adjustment.Apply(tt.price); *got != *tt.want {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkPriceService_Adjust(t *testing.T) {
+	newService := func() (*BulkPriceService, *memoryRepository) {
+		archived := Archived
+		repository := newMemoryRepository(
+			&Product{ID: s("p1"), CategoryID: s("tools"), Price: m(10), Prices: money.Prices{money.New(900, "EUR")}},
+			&Product{ID: s("p2"), CategoryID: s("drills"), Price: m(20), Tags: []string{"sale"}, Variants: []*Variant{{SKU: s("D-1"), Price: m(30)}}},
+			&Product{ID: s("p3"), CategoryID: s("garden"), Price: m(40), Tags: []string{"sale"}},
+			&Product{ID: s("p4"), CategoryID: s("tools"), Price: m(50), Status: &archived},
+		)
+		catalog := newMemoryCategories(
+			&categories.Category{ID: s("tools")},
+			&categories.Category{ID: s("drills"), ParentCategoryID: s("tools")},
+			&categories.Category{ID: s("garden")},
+		)
+
+		return NewBulkPriceService(repository, catalog), repository
+	}
+	raise := Adjustment{Kind: PercentageAdjustment, Percentage: 10}
+
+	tests := []struct {
+		name      string
+		selection *Selection
+		want      map[string]int64
+		prices    int
+	}{
+		{name: "Category", selection: &Selection{CategoryID: s("tools")}, want: map[string]int64{"p1": 1100}, prices: 2},
+		{name: "Recursive category", selection: &Selection{CategoryID: s("tools"), Recursive: true}, want: map[string]int64{"p1": 1100, "p2": 2200}, prices: 4},
+		{name: "IDs", selection: &Selection{IDs: []*string{s("p3")}}, want: map[string]int64{"p3": 4400}, prices: 1},
+		{
+			name:      "Filter",
+			selection: &Selection{Filter: func(product *Product) bool { return product.HasTag("sale") }},
+			want:      map[string]int64{"p2": 2200, "p3": 4400},
+			prices:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repository := newService()
+			report, err := service.Adjust(tt.selection, raise, nil)
+
+			if err != nil || len(report.Prices) != tt.prices || len(report.Updated) != len(tt.want) {
+				t.Fatalf("Adjust() report = %+v, error = %v", report, err)
+			}
+
+			for id, amount := range tt.want {
+				if got := repository.items[id].Price.Amount; got != amount {
+					t.Errorf("Adjust() price of %s = %d, want %d", id, got, amount)
+				}
+			}
+		})
+	}
+
+	t.Run("Dry run", func(t *testing.T) {
+		service, repository := newService()
+		report, err := service.Adjust(&Selection{CategoryID: s("tools"), Recursive: true}, raise, &BulkPriceOptions{DryRun: true})
+
+		if err != nil || !report.DryRun || len(report.Prices) != 4 {
+			t.Fatalf("Adjust() report = %+v, error = %v", report, err)
+		}
+
+		if repository.items["p1"].Price.Amount != 1000 || repository.items["p2"].Variants[0].Price.Amount != 3000 {
+			t.Errorf("Adjust() changed the products on a dry run")
+		}
+
+		if variant := report.Prices[3]; *variant.SKU != "D-1" || variant.Previous.Amount != 3000 || variant.Next.Amount != 3300 {
+			t.Errorf("Adjust() variant preview = %+v", variant)
+		}
+	})
+
+	t.Run("Throttled batches", func(t *testing.T) {
+		service, _ := newService()
+		pauses := make([]time.Duration, 0)
+		service.sleep = func(duration time.Duration) {
+			pauses = append(pauses, duration)
+		}
+		progress := make([]int, 0)
+		options := &BulkPriceOptions{BatchSize: 1, Pause: time.Second, Progress: func(current *BulkPriceProgress) {
+			progress = append(progress, current.Done)
+		}}
+
+		if _, err := service.Adjust(&Selection{IDs: []*string{s("p1"), s("p2"), s("p3")}}, raise, options); err != nil {
+			t.Fatalf("Adjust() error = %v", err)
+		}
+
+		if len(pauses) != 2 || len(progress) != 3 || progress[2] != 3 {
+			t.Errorf("Adjust() pauses = %v, progress = %v", pauses, progress)
+		}
+	})
+
+	t.Run("Keeps the edits made during the pauses", func(t *testing.T) {
+		service, repository := newService()
+		service.sleep = func(duration time.Duration) {
+			renamed := *repository.items["p3"]
+			renamed.Name = s("Edited")
+			repository.items["p3"] = &renamed
+		}
+		options := &BulkPriceOptions{BatchSize: 1, Pause: time.Second}
+
+		if _, err := service.Adjust(&Selection{IDs: []*string{s("p1"), s("p3")}}, raise, options); err != nil {
+			t.Fatalf("Adjust() error = %v", err)
+		}
+
+		if p3 := repository.items["p3"]; p3.Name == nil || *p3.Name != "Edited" || p3.Price.Amount != 4400 {
+			t.Errorf("Adjust() product = %+v, want the edit and the new price", p3)
+		}
+	})
+
+	t.Run("Only reports the written products", func(t *testing.T) {
+		service, repository := newService()
+		service.Repository = &failingUpdates{memoryRepository: repository, after: 1}
+		report, err := service.Adjust(&Selection{IDs: []*string{s("p1"), s("p3")}}, raise, &BulkPriceOptions{BatchSize: 1})
+
+		if err == nil || len(report.Updated) != 1 || *report.Updated[0] != "p1" || len(report.Prices) != 2 {
+			t.Errorf("Adjust() report = %+v, error = %v", report, err)
+		}
+	})
+
+	t.Run("Invalid adjustment", func(t *testing.T) {
+		service, _ := newService()
+
+		if _, err := service.Adjust(&Selection{CategoryID: s("tools")}, Adjustment{Kind: PercentageAdjustment, Percentage: -100}, nil); err == nil {
+			t.Errorf("Adjust() accepted a -100%% adjustment")
+		}
+	})
+}
+
+// failingUpdates fails every update after the first ones
+type failingUpdates struct {
+	*memoryRepository
+	after int
+}
+
+func (repository *failingUpdates) Update(id *string, product *Product) error {
+	if repository.after == 0 {
+		return fmt.Errorf("the update of %s failed", *id)
+	}
+
+	repository.after--
+
+	return repository.memoryRepository.Update(id, product)
+}
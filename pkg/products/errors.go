@@ -0,0 +1,74 @@
+package products
+
+import "fmt"
+
+// NotFoundError is returned when the requested product does not exist
+type NotFoundError struct {
+	ID string
+}
+
+func (err NotFoundError) Error() string {
+	return fmt.Sprintf("the product \"%s\" does not exist", err.ID)
+}
+
+// CategoryUnavailableError is returned when a product is assigned
+// to a category that does not exist or that is not visible
+type CategoryUnavailableError struct {
+	ID     string
+	Reason string
+}
+
+func (err CategoryUnavailableError) Error() string {
+	return fmt.Sprintf("the category \"%s\" %s", err.ID, err.Reason)
+}
+
+// BulkError has the errors of the elements of a bulk operation
+// indexed by their position, nothing is stored when it is returned
+type BulkError map[int]error
+
+func (err BulkError) Error() string {
+	return fmt.Sprintf("%d of the products are not valid", len(err))
+}
+
+// PartialStoreError is returned when a bulk creation fails after storing some
+// of the products, Index is the position of the product that could not be stored
+type PartialStoreError struct {
+	Index  int
+	Stored []*string
+	Err    error
+}
+
+func (err PartialStoreError) Error() string {
+	return fmt.Sprintf("only %d of the products were stored, the product %d failed: %s", len(err.Stored), err.Index, err.Err)
+}
+
+// InvalidTransitionError is returned when the lifecycle of
+// the product does not allow the change of status
+type InvalidTransitionError struct {
+	ID   string
+	From Status
+	To   Status
+}
+
+func (err InvalidTransitionError) Error() string {
+	return fmt.Sprintf("the product \"%s\" can not go from %s to %s", err.ID, err.From, err.To)
+}
+
+// ArchivedProductError is returned when an archived product is edited,
+// the product must be restored before changing any of its attributes
+type ArchivedProductError struct {
+	ID string
+}
+
+func (err ArchivedProductError) Error() string {
+	return fmt.Sprintf("the product \"%s\" is archived, restore it before editing it", err.ID)
+}
+
+// DuplicateSKUError is returned when another product already uses the SKU
+type DuplicateSKUError struct {
+	SKU string
+}
+
+func (err DuplicateSKUError) Error() string {
+	return fmt.Sprintf("the SKU \"%s\" is already used by another product", err.SKU)
+}
@@ -0,0 +1,204 @@
+package products
+
+import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+)
+
+// PriceChange records that the price of the product in the currency changed, SKU
+// is set when the price is the one of a variant, Previous is nil for the first price
+type PriceChange struct {
+	ProductID *string         `json:"productId"`
+	SKU       *string         `json:"sku,omitempty"`
+	Price     *money.Money    `json:"price"`
+	Previous  *money.Money    `json:"previous,omitempty"`
+	ChangedAt *timestamp.Time `json:"changedAt"`
+}
+
+type PriceHistoryRepository interface {
+	Record(change *PriceChange) error
+
+	// History returns the changes of the currency inside the inclusive range, the oldest go
+	// first, a nil SKU returns the changes of the product price and not the ones of the variants
+	History(productID, sku *string, currency money.Currency, from, to time.Time) ([]*PriceChange, error)
+
+	// LastBefore returns the last change of the currency before the given time, nil when there is none
+	LastBefore(productID, sku *string, currency money.Currency, at time.Time) (*PriceChange, error)
+}
+
+// PriceChanges compares the prices of both versions of the product, previous is
+// nil for new products, the removed currencies are not recorded as changes, every
+// variant records its own changes, including the ones of the price it inherits
+func PriceChanges(previous, next *Product, at time.Time) []*PriceChange {
+	changes := make([]*PriceChange, 0)
+	prices := append(money.Prices{next.Price}, next.Prices...)
+
+	for _, price := range prices {
+		if price == nil {
+			continue
+		}
+
+		var current *money.Money
+
+		if previous != nil {
+			current = previous.PriceIn(price.Currency)
+		}
+
+		if change := priceChange(next.ID, nil, current, price, at); change != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	for _, variant := range next.Variants {
+		price := next.PriceOf(variant)
+
+		if variant.SKU == nil || price == nil {
+			continue
+		}
+
+		if change := priceChange(next.ID, variant.SKU, previous.variantPrice(*variant.SKU, price.Currency), price, at); change != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// variantPrice is the price of the variant in the currency, nil when the product or the variant did not exist
+func (product *Product) variantPrice(sku string, currency money.Currency) *money.Money {
+	if product == nil {
+		return nil
+	}
+
+	variant := product.Variant(sku)
+
+	if variant == nil {
+		return nil
+	}
+
+	if price := product.PriceOf(variant); price != nil && price.Currency == currency {
+		return price
+	}
+
+	return nil
+}
+
+func priceChange(productID, sku *string, current, price *money.Money, at time.Time) *PriceChange {
+	if current != nil && current.Amount == price.Amount {
+		return nil
+	}
+
+	return &PriceChange{
+		ProductID: productID,
+		SKU:       sku,
+		Price:     price,
+		Previous:  current,
+		ChangedAt: timestamp.New(at),
+	}
+}
+
+type PriceHistoryService struct {
+	History  PriceHistoryRepository
+	Products ProductRepository
+	Clock    clock.Clock
+}
+
+func NewPriceHistoryService(history PriceHistoryRepository, products ProductRepository) *PriceHistoryService {
+	return &PriceHistoryService{History: history, Products: products, Clock: clock.System}
+}
+
+// Changes returns the changes of the currency during the last period
+func (service *PriceHistoryService) Changes(productID *string, currency money.Currency, period time.Duration) ([]*PriceChange, error) {
+	return service.VariantChanges(productID, nil, currency, period)
+}
+
+// VariantChanges returns the changes of the price of the variant, see Changes
+func (service *PriceHistoryService) VariantChanges(productID, sku *string, currency money.Currency, period time.Duration) ([]*PriceChange, error) {
+	now := service.Clock.Now()
+
+	return service.History.History(productID, sku, currency, now.Add(-period), now)
+}
+
+// LowestPrice returns the lowest price the product had in the currency during the
+// last period, including the price in effect when the period started, nil when
+// the product had no price in the currency, the discount regulations use 30 days,
+// the history only knows the changes recorded since it was enabled, so the price
+// before the oldest change is taken from it and the current price of the product
+// is used when nothing changed since then
+func (service *PriceHistoryService) LowestPrice(productID *string, currency money.Currency, period time.Duration) (*money.Money, error) {
+	return service.LowestVariantPrice(productID, nil, currency, period)
+}
+
+// LowestVariantPrice returns the lowest price of the variant, see LowestPrice
+func (service *PriceHistoryService) LowestVariantPrice(productID, sku *string, currency money.Currency, period time.Duration) (*money.Money, error) {
+	now := service.Clock.Now()
+	start := now.Add(-period)
+	changes, err := service.History.History(productID, sku, currency, start, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]*money.Money, 0, len(changes)+1)
+
+	for _, change := range changes {
+		prices = append(prices, change.Price)
+	}
+
+	initial, err := service.initialPrice(productID, sku, currency, start, changes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if initial != nil {
+		prices = append(prices, initial)
+	}
+
+	var lowest *money.Money
+
+	for _, price := range prices {
+		if lowest == nil || price.Amount < lowest.Amount {
+			lowest = price
+		}
+	}
+
+	return lowest, nil
+}
+
+// initialPrice finds the price in effect when the period started, changes are the
+// ones of the period, the oldest first
+func (service *PriceHistoryService) initialPrice(productID, sku *string, currency money.Currency, start time.Time, changes []*PriceChange) (*money.Money, error) {
+	initial, err := service.History.LastBefore(productID, sku, currency, start)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if initial != nil {
+		return initial.Price, nil
+	}
+
+	if len(changes) > 0 {
+		return changes[0].Previous, nil
+	}
+
+	if service.Products == nil {
+		return nil, nil
+	}
+
+	product, err := service.Products.FindOne(productID)
+
+	if err != nil || product == nil {
+		return nil, err
+	}
+
+	if sku != nil {
+		return product.variantPrice(*sku, currency), nil
+	}
+
+	return product.PriceIn(currency), nil
+}
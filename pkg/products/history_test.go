@@ -0,0 +1,180 @@
+package products
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/ids"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+)
+
+func TestPriceChanges(t *testing.T) {
+	previous := &Product{ID: s("p1"), Price: m(10), Prices: money.Prices{money.New(900, "EUR")}}
+	next := &Product{ID: s("p1"), Price: m(12), Prices: money.Prices{money.New(900, "EUR"), money.New(4000000, "COP")}}
+	changes := PriceChanges(previous, next, time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(changes) != 2 || changes[0].Previous.Amount != 1000 || changes[0].Price.Amount != 1200 || changes[1].Previous != nil {
+		t.Errorf("PriceChanges() = %v, want the USD and the COP changes", changes)
+	}
+}
+
+func TestPriceChangesVariants(t *testing.T) {
+	previous := &Product{ID: s("p1"), Price: m(10), Variants: []*Variant{{SKU: s("red"), Price: m(11)}, {SKU: s("blue")}}}
+	next := &Product{ID: s("p1"), Price: m(10), Variants: []*Variant{{SKU: s("red"), Price: m(13)}, {SKU: s("blue")}, {SKU: s("green"), Price: m(9)}}}
+	changes := PriceChanges(previous, next, time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(changes) != 2 || *changes[0].SKU != "red" || changes[0].Previous.Amount != 1100 || changes[0].Price.Amount != 1300 ||
+		*changes[1].SKU != "green" || changes[1].Previous != nil {
+		t.Fatalf("PriceChanges() = %v, want the red and the new green variants", changes)
+	}
+
+	next.Price = m(12)
+	changes = PriceChanges(previous, next, time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(changes) != 4 || changes[0].SKU != nil || *changes[2].SKU != "blue" || changes[2].Price.Amount != 1200 {
+		t.Errorf("PriceChanges() = %v, want the inherited price of the blue variant recorded", changes)
+	}
+}
+
+func TestPriceHistoryService_LowestVariantPrice(t *testing.T) {
+	now := clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+	history := &memoryHistory{}
+	_ = history.Record(&PriceChange{ProductID: s("p1"), Price: m(5), ChangedAt: timestamp.New(now.Now().AddDate(0, 0, -5))})
+	_ = history.Record(&PriceChange{ProductID: s("p1"), SKU: s("red"), Price: m(14), Previous: m(9), ChangedAt: timestamp.New(now.Now().AddDate(0, 0, -5))})
+	repository := newMemoryRepository(&Product{ID: s("p1"), Price: m(5), Variants: []*Variant{{SKU: s("red"), Price: m(14)}, {SKU: s("blue")}}})
+	service := NewPriceHistoryService(history, repository)
+	service.Clock = now
+
+	if lowest, err := service.LowestVariantPrice(s("p1"), s("red"), "USD", 30*24*time.Hour); err != nil || lowest.Amount != 900 {
+		t.Errorf("LowestVariantPrice() = %v, error = %v, want the variant price before its change", lowest, err)
+	}
+
+	if lowest, err := service.LowestVariantPrice(s("p1"), s("blue"), "USD", 30*24*time.Hour); err != nil || lowest.Amount != 500 {
+		t.Errorf("LowestVariantPrice() = %v, error = %v, want the inherited price", lowest, err)
+	}
+}
+
+func TestPriceHistoryService_LowestPrice(t *testing.T) {
+	now := clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+	history := &memoryHistory{}
+	record := func(daysAgo int, amount float64) {
+		_ = history.Record(&PriceChange{ProductID: s("p1"), Price: m(amount), ChangedAt: timestamp.New(now.Now().AddDate(0, 0, -daysAgo))})
+	}
+	record(60, 8)
+	record(40, 15)
+	record(20, 10)
+	record(5, 12)
+	// The history of p2 started with a change, p3 never changed since it was enabled
+	_ = history.Record(&PriceChange{ProductID: s("p2"), Price: m(11), Previous: m(7), ChangedAt: timestamp.New(now.Now().AddDate(0, 0, -10))})
+	repository := newMemoryRepository(&Product{ID: s("p2"), Price: m(11)}, &Product{ID: s("p3"), Price: m(9)})
+	service := NewPriceHistoryService(history, repository)
+	service.Clock = now
+
+	tests := []struct {
+		name      string
+		productID string
+		period    time.Duration
+		want      int64
+	}{
+		{name: "Includes the price in effect when the period starts", productID: "p1", period: 10 * 24 * time.Hour, want: 1000},
+		{name: "Last 30 days", productID: "p1", period: 30 * 24 * time.Hour, want: 1000},
+		{name: "Older prices", productID: "p1", period: 50 * 24 * time.Hour, want: 800},
+		{name: "Only the price in effect", productID: "p1", period: 24 * time.Hour, want: 1200},
+		{name: "Price before the oldest change", productID: "p2", period: 30 * 24 * time.Hour, want: 700},
+		{name: "Current price without changes", productID: "p3", period: 30 * 24 * time.Hour, want: 900},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lowest, err := service.LowestPrice(s(tt.productID), "USD", tt.period)
+
+			if err != nil || lowest.Amount != tt.want {
+				t.Errorf("LowestPrice() = %v, error = %v, want %d", lowest, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceScheduleService_ApplyDue(t *testing.T) {
+	now := clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+	repository := newMemoryRepository(&Product{ID: s("p1"), Name: s("Drill"), Price: m(10), CategoryID: s("tools")})
+	schedule := &memorySchedule{items: map[string]*ScheduledPriceChange{}}
+	service := NewPriceScheduleService(repository, schedule)
+	service.IDs = &ids.Sequence{}
+	service.Clock = now
+
+	if _, err := service.Schedule(s("p1"), m(8), now.Now()); err == nil {
+		t.Errorf("Schedule() expected an error for a change that is not in the future")
+	}
+
+	if _, err := service.Schedule(s("unknown"), m(8), now.Now().Add(time.Hour)); err == nil {
+		t.Errorf("Schedule() expected a NotFoundError")
+	}
+
+	_, _ = service.Schedule(s("p1"), m(8), now.Now().Add(time.Hour))
+	_, _ = service.Schedule(s("p1"), money.New(700, "EUR"), now.Now().Add(2*time.Hour))
+	now.Advance(time.Hour)
+	applied, err := service.ApplyDue()
+
+	if err != nil || len(applied) != 1 || repository.items["p1"].Price.Amount != 800 {
+		t.Fatalf("ApplyDue() applied = %v, error = %v", applied, err)
+	}
+
+	if applied, _ = service.ApplyDue(); len(applied) != 0 {
+		t.Errorf("ApplyDue() applied the change twice")
+	}
+
+	now.Advance(time.Hour)
+	_, _ = service.ApplyDue()
+
+	if price := repository.items["p1"].PriceIn("EUR"); price == nil || price.Amount != 700 {
+		t.Errorf("ApplyDue() EUR price = %v, want 7.00 EUR", price)
+	}
+}
+
+func TestPriceScheduleService_ApplyDueArchived(t *testing.T) {
+	now := clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+	archived := Archived
+	repository := newMemoryRepository(&Product{ID: s("p1"), Name: s("Drill"), Price: m(10), CategoryID: s("tools"), Status: &archived})
+	service := NewPriceScheduleService(repository, &memorySchedule{items: map[string]*ScheduledPriceChange{}})
+	service.IDs = &ids.Sequence{}
+	service.Clock = now
+
+	_, _ = service.Schedule(s("p1"), m(8), now.Now().Add(time.Hour))
+	now.Advance(time.Hour)
+
+	if applied, err := service.ApplyDue(); err != nil || len(applied) != 0 || repository.items["p1"].Price.Amount != 1000 {
+		t.Fatalf("ApplyDue() applied = %v, error = %v, want the archived product untouched", applied, err)
+	}
+
+	draft := Draft
+	repository.items["p1"].Status = &draft
+
+	if applied, _ := service.ApplyDue(); len(applied) != 1 || repository.items["p1"].Price.Amount != 800 {
+		t.Errorf("ApplyDue() applied = %v, want the pending change once the product is restored", applied)
+	}
+}
+
+func TestPriceScheduleService_ApplyDueVariant(t *testing.T) {
+	now := clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+	repository := newMemoryRepository(&Product{ID: s("p1"), Name: s("Drill"), Price: m(10), CategoryID: s("tools"), Variants: []*Variant{{SKU: s("red")}}})
+	service := NewPriceScheduleService(repository, &memorySchedule{items: map[string]*ScheduledPriceChange{}})
+	service.IDs = &ids.Sequence{}
+	service.Clock = now
+
+	if _, err := service.ScheduleVariant(s("p1"), s("green"), m(8), now.Now().Add(time.Hour)); err == nil {
+		t.Errorf("ScheduleVariant() expected an error for an unknown variant")
+	}
+
+	if _, err := service.ScheduleVariant(s("p1"), s("red"), money.New(800, "EUR"), now.Now().Add(time.Hour)); err == nil {
+		t.Errorf("ScheduleVariant() expected an error for a currency other than the main one")
+	}
+
+	_, _ = service.ScheduleVariant(s("p1"), s("red"), m(8), now.Now().Add(time.Hour))
+	now.Advance(time.Hour)
+
+	if applied, err := service.ApplyDue(); err != nil || len(applied) != 1 || repository.items["p1"].Variant("red").Price.Amount != 800 || repository.items["p1"].Price.Amount != 1000 {
+		t.Errorf("ApplyDue() applied = %v, error = %v, want only the variant repriced", applied, err)
+	}
+}
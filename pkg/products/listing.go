@@ -0,0 +1,100 @@
+package products
+
+import (
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
+)
+
+// Listing is the entry of a product in the public listings, the variants are collapsed
+// under their parent into the price range and the option values that can be bought
+type Listing struct {
+	ProductID  *string               `json:"productId"`
+	Name       *string               `json:"name"`
+	CategoryID *string               `json:"categoryId"`
+	Multimedia multimedia.Collection `json:"multimedia"`
+	MinPrice   *money.Money          `json:"minPrice"`
+	MaxPrice   *money.Money          `json:"maxPrice"`
+	Variants   int                   `json:"variants"`
+	Options    map[string][]string   `json:"options,omitempty"`
+}
+
+// Collapse returns one listing per product, in the same order
+func Collapse(items []*Product) []*Listing {
+	result := make([]*Listing, len(items))
+
+	for index, item := range items {
+		min, max := item.PriceRange()
+		result[index] = &Listing{
+			ProductID:  item.ID,
+			Name:       item.Name,
+			CategoryID: item.CategoryID,
+			Multimedia: item.Multimedia,
+			MinPrice:   min,
+			MaxPrice:   max,
+			Variants:   len(item.Variants),
+			Options:    item.availableOptions(),
+		}
+	}
+
+	return result
+}
+
+// availableOptions has the values of every option axis used by an
+// available variant, in the order of the values of the axis
+func (product *Product) availableOptions() map[string][]string {
+	if len(product.Options) == 0 {
+		return nil
+	}
+
+	used := map[string]bool{}
+
+	for _, variant := range product.Variants {
+		if !variant.IsAvailable() {
+			continue
+		}
+
+		for name, value := range variant.Options {
+			used[name+"="+value] = true
+		}
+	}
+
+	result := map[string][]string{}
+
+	for _, axis := range product.Options {
+		if axis.Name == nil {
+			continue
+		}
+
+		values := make([]string, 0, len(axis.Values))
+
+		for _, value := range axis.Values {
+			if value != nil && used[*axis.Name+"="+*value] {
+				values = append(values, *value)
+			}
+		}
+
+		result[*axis.Name] = values
+	}
+
+	return result
+}
+
+// ListAvailable collapses the variants of the products available in the category
+func (service *ProductService) ListAvailable(categoryID *string) ([]*Listing, error) {
+	items, err := service.Repository.FindAvailable(categoryID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := service.factory().Clock.Now()
+	available := make([]*Product, 0, len(items))
+
+	for _, item := range items {
+		if item.IsAvailable(now) {
+			available = append(available, item)
+		}
+	}
+
+	return Collapse(available), nil
+}
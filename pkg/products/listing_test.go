@@ -0,0 +1,36 @@
+package products
+
+import "testing"
+
+func TestProductService_ListAvailable(t *testing.T) {
+	sold := shirt("p1")
+	sold.Variants = []*Variant{variant("SH-S-RED", "S", "red"), variant("SH-M-BLUE", "M", "blue"), variant("SH-M-RED", "M", "red")}
+	sold.Variants[1].Price = m(25)
+	sold.Variants[2].Available = b(false)
+	soldOut := shirt("p2")
+	soldOut.Variants = []*Variant{{SKU: s("SH2-S-RED"), Options: map[string]string{"size": "S", "colour": "red"}, Available: b(false)}}
+	service := NewProductService(newMemoryRepository(sold, soldOut, scheduled("p3", Published, -1, -1)), catalog())
+	service.Factory = testFactory()
+
+	items, err := service.ListAvailable(s("tools"))
+
+	if err != nil || len(items) != 2 {
+		t.Fatalf("ListAvailable() = %v, error = %v, want the sold out product collapsed away", items, err)
+	}
+
+	var listing *Listing
+
+	for _, item := range items {
+		if *item.ProductID == "p1" {
+			listing = item
+		}
+	}
+
+	if listing == nil || listing.Variants != 3 || listing.MinPrice.Amount != m(20).Amount || listing.MaxPrice.Amount != m(25).Amount {
+		t.Fatalf("ListAvailable() listing = %+v", listing)
+	}
+
+	if sizes, colours := listing.Options["size"], listing.Options["colour"]; len(sizes) != 2 || len(colours) != 2 {
+		t.Errorf("ListAvailable() options = %v, want both sizes and colours", listing.Options)
+	}
+}
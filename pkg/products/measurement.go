@@ -0,0 +1,164 @@
+package products
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/units"
+	"github.com/alejo-lapix/products-go/pkg/validation"
+)
+
+// Normalize replaces the unit with its canonical symbol, "Kilogramos" turns into "kg",
+// the unregistered units are left as they are and rejected by the validation
+func (measurement *UnitOfMeasurement) Normalize() {
+	if measurement == nil || measurement.Unit == nil {
+		return
+	}
+
+	if symbol, err := units.Normalize(*measurement.Unit); err == nil {
+		measurement.Unit = &symbol
+	}
+}
+
+// In returns the quantity in the given unit, it fails with units.IncompatibleUnitsError
+// when the unit measures another dimension, like kilograms and litres
+func (measurement *UnitOfMeasurement) In(unit string) (float64, error) {
+	if measurement == nil || measurement.Quantity == nil || measurement.Unit == nil {
+		return 0, fmt.Errorf("the unit of measurement is incomplete")
+	}
+
+	return units.Convert(*measurement.Quantity, *measurement.Unit, unit)
+}
+
+// UnitPrice is the price of one reference unit, like the price per kilogram,
+// it compares the products sold in different sizes, a 500 g pack with a 1 kg one
+type UnitPrice struct {
+	Price *money.Money `json:"price"`
+	Unit  string       `json:"unit"`
+}
+
+// UnitPrice returns nil when the product does not have a registered unit of measurement
+func (product *Product) UnitPrice() *UnitPrice {
+	return unitPrice(product.Price, product.UnitOfMeasurement)
+}
+
+// UnitPriceOf uses the unit of measurement of the product when the variant does not have one
+func (product *Product) UnitPriceOf(variant *Variant) *UnitPrice {
+	measurement := variant.UnitOfMeasurement
+
+	if measurement == nil {
+		measurement = product.UnitOfMeasurement
+	}
+
+	return unitPrice(product.PriceOf(variant), measurement)
+}
+
+// MarshalJSON adds the unitPrice of the product and of its variants, they are derived
+// from the price and the unit of measurement so they are never stored nor decoded
+func (product *Product) MarshalJSON() ([]byte, error) {
+	type plain Product
+	type variant struct {
+		*Variant
+		UnitPrice *UnitPrice `json:"unitPrice,omitempty"`
+	}
+
+	variants := make([]*variant, len(product.Variants))
+
+	for index, item := range product.Variants {
+		variants[index] = &variant{Variant: item}
+
+		if item != nil {
+			variants[index].UnitPrice = product.UnitPriceOf(item)
+		}
+	}
+
+	return json.Marshal(struct {
+		*plain
+		Variants  []*variant `json:"variants,omitempty"`
+		UnitPrice *UnitPrice `json:"unitPrice,omitempty"`
+	}{(*plain)(product), variants, product.UnitPrice()})
+}
+
+func unitPrice(price *money.Money, measurement *UnitOfMeasurement) *UnitPrice {
+	if price == nil || measurement == nil || measurement.Unit == nil {
+		return nil
+	}
+
+	reference, err := units.Reference(*measurement.Unit)
+
+	if err != nil {
+		return nil
+	}
+
+	quantity, err := measurement.In(reference.Symbol)
+
+	if err != nil || quantity <= 0 {
+		return nil
+	}
+
+	return &UnitPrice{Price: price.Multiply(1 / quantity), Unit: reference.Symbol}
+}
+
+// normalizeMeasurements normalizes the units of the product and of its variants
+func normalizeMeasurements(product *Product) {
+	product.UnitOfMeasurement.Normalize()
+
+	for _, variant := range product.Variants {
+		if variant != nil {
+			variant.UnitOfMeasurement.Normalize()
+		}
+	}
+}
+
+// checkUnits only accepts the units registered in the units package, the units stored in
+// current are kept as they are, so the products saved with free-form units like "bolsa"
+// can still be updated while their units do not change, current is nil for new products
+func checkUnits(product, current *Product) error {
+	errs := validation.Errors{}
+	var stored *UnitOfMeasurement
+
+	if current != nil {
+		stored = current.UnitOfMeasurement
+	}
+
+	errs = append(errs, checkUnit("unitOfMeasurement", product.UnitOfMeasurement, stored)...)
+
+	for index, variant := range product.Variants {
+		if variant == nil {
+			continue
+		}
+
+		stored = nil
+
+		if current != nil && variant.SKU != nil {
+			if previous := current.Variant(*variant.SKU); previous != nil {
+				stored = previous.UnitOfMeasurement
+			}
+		}
+
+		errs = append(errs, checkUnit(fmt.Sprintf("variants[%d].unitOfMeasurement", index), variant.UnitOfMeasurement, stored)...)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func checkUnit(path string, measurement, stored *UnitOfMeasurement) validation.Errors {
+	if measurement == nil || measurement.Unit == nil {
+		return nil
+	}
+
+	if stored != nil && stored.Unit != nil && *stored.Unit == *measurement.Unit {
+		return nil
+	}
+
+	if _, err := units.Lookup(*measurement.Unit); err != nil {
+		return validation.Errors{validation.NewFieldError(path+".unit", "unit", "must be a registered unit")}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package products
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+)
+
+func measure(quantity float64, unit string) *UnitOfMeasurement {
+	return &UnitOfMeasurement{Quantity: &quantity, Unit: &unit}
+}
+
+func TestProduct_UnitPrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		product *Product
+		want    *UnitPrice
+	}{
+		{name: "Per kilogram", product: &Product{Price: m(5), UnitOfMeasurement: measure(500, "g")}, want: &UnitPrice{Price: m(10), Unit: "kg"}},
+		{name: "Per litre", product: &Product{Price: m(3), UnitOfMeasurement: measure(1.5, "L")}, want: &UnitPrice{Price: m(2), Unit: "l"}},
+		{name: "Per unit", product: &Product{Price: m(12), UnitOfMeasurement: measure(1, "dozen")}, want: &UnitPrice{Price: m(1), Unit: "unit"}},
+		{name: "Rounds the price", product: &Product{Price: money.New(1000, "USD"), UnitOfMeasurement: measure(3, "kg")}, want: &UnitPrice{Price: money.New(333, "USD"), Unit: "kg"}},
+		{name: "Unknown unit", product: &Product{Price: m(5), UnitOfMeasurement: measure(1, "bolsa")}},
+		{name: "Without unit", product: &Product{Price: m(5)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.product.UnitPrice()
+
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("UnitPrice() = %v, want %v", got, tt.want)
+			}
+
+			if got != nil && (*got.Price != *tt.want.Price || got.Unit != tt.want.Unit) {
+				t.Errorf("UnitPrice() = %v %s, want %v %s", got.Price, got.Unit, tt.want.Price, tt.want.Unit)
+			}
+		})
+	}
+}
+
+func TestProduct_UnitPriceOf(t *testing.T) {
+	product := &Product{Price: m(5), UnitOfMeasurement: measure(500, "g"), Variants: []*Variant{
+		{SKU: s("SMALL")},
+		{SKU: s("LARGE"), Price: m(8), UnitOfMeasurement: measure(1, "kg")},
+	}}
+
+	if got := product.UnitPriceOf(product.Variants[0]); got == nil || got.Price.Amount != 1000 {
+		t.Errorf("UnitPriceOf() = %v, want the unit price of the product", got)
+	}
+
+	if got := product.UnitPriceOf(product.Variants[1]); got == nil || got.Price.Amount != 800 {
+		t.Errorf("UnitPriceOf() = %v, want 8 per kg", got)
+	}
+}
+
+func TestFactory_NewProductUnit(t *testing.T) {
+	product, err := testFactory().NewProduct(s("Rice"), s(""), s("food"), m(5), measure(1, "Kilogramos"), nil)
+
+	if err != nil || *product.UnitOfMeasurement.Unit != "kg" {
+		t.Fatalf("NewProduct() unit = %v, error = %v, want kg", product, err)
+	}
+
+	if _, err = testFactory().NewProduct(s("Rice"), s(""), s("food"), m(5), measure(1, "bolsa"), nil); err == nil {
+		t.Errorf("NewProduct() accepted an unknown unit")
+	}
+}
+
+func TestProductService_UpdateProductLegacyUnit(t *testing.T) {
+	legacy := scheduled("p1", Published, -1, -1)
+	legacy.UnitOfMeasurement = measure(1, "bolsa")
+	service := NewProductService(newMemoryRepository(legacy), catalog())
+	service.Factory = testFactory()
+
+	renamed := *legacy
+	renamed.Name = s("Renamed")
+
+	if _, err := service.UpdateProduct(s("p1"), &renamed); err != nil {
+		t.Errorf("UpdateProduct() error = %v, want the stored unit accepted", err)
+	}
+
+	changed := *legacy
+	changed.UnitOfMeasurement = measure(1, "paqete")
+
+	if _, err := service.UpdateProduct(s("p1"), &changed); err == nil {
+		t.Errorf("UpdateProduct() accepted a new unknown unit")
+	}
+}
+
+func TestProduct_MarshalJSON(t *testing.T) {
+	product := &Product{ID: s("p1"), Price: m(5), UnitOfMeasurement: measure(500, "g"), Variants: []*Variant{
+		{SKU: s("LARGE"), Price: m(8), UnitOfMeasurement: measure(1, "kg")},
+	}}
+	data, err := json.Marshal(product)
+
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+
+	decoded := struct {
+		ID        string
+		UnitPrice *UnitPrice `json:"unitPrice"`
+		Variants  []struct {
+			SKU       string     `json:"sku"`
+			UnitPrice *UnitPrice `json:"unitPrice"`
+		} `json:"variants"`
+	}{}
+
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.ID != "p1" || decoded.UnitPrice == nil || decoded.UnitPrice.Price.Amount != 1000 || decoded.UnitPrice.Unit != "kg" {
+		t.Errorf("MarshalJSON() = %s, want the unit price of the product", data)
+	}
+
+	if len(decoded.Variants) != 1 || decoded.Variants[0].SKU != "LARGE" || decoded.Variants[0].UnitPrice.Price.Amount != 800 {
+		t.Errorf("MarshalJSON() = %s, want the unit price of the variant", data)
+	}
+}
@@ -0,0 +1,414 @@
+package products
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/ids"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+)
+
+func testFactory() *Factory {
+	return NewFactory(&ids.Sequence{}, clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func b(input bool) *bool {
+	return &input
+}
+
+type memoryRepository struct {
+	items map[string]*Product
+	trash map[string]*Product
+	clock clock.Clock
+}
+
+func newMemoryRepository(items ...*Product) *memoryRepository {
+	repository := &memoryRepository{items: map[string]*Product{}, trash: map[string]*Product{}, clock: clock.System}
+
+	for _, item := range items {
+		repository.items[*item.ID] = item
+	}
+
+	return repository
+}
+
+func (repository *memoryRepository) Store(product *Product) error {
+	if _, ok := repository.items[*product.ID]; ok {
+		return fmt.Errorf("duplicated")
+	}
+
+	if err := repository.checkSKUs(product); err != nil {
+		return err
+	}
+
+	repository.items[*product.ID] = product
+
+	return nil
+}
+
+func (repository *memoryRepository) Update(id *string, product *Product) error {
+	if _, ok := repository.items[*id]; !ok {
+		return fmt.Errorf("not found")
+	}
+
+	if err := repository.checkSKUs(product); err != nil {
+		return err
+	}
+
+	repository.items[*id] = product
+
+	return nil
+}
+
+// checkSKUs also looks at the deleted products, they keep their SKUs
+func (repository *memoryRepository) checkSKUs(product *Product) error {
+	for _, sku := range product.SKUs() {
+		for _, items := range []map[string]*Product{repository.items, repository.trash} {
+			for _, item := range items {
+				if *item.ID != *product.ID && item.Variant(sku) != nil {
+					return DuplicateSKUError{SKU: sku}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (repository *memoryRepository) FindBySKU(sku *string) (*Product, *Variant, error) {
+	for id, item := range repository.items {
+		if item.Variant(*sku) != nil {
+			product, _ := repository.FindOne(&id)
+
+			return product, product.Variant(*sku), nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// FindOne returns a copy, like a real database would do
+func (repository *memoryRepository) FindOne(id *string) (*Product, error) {
+	item, ok := repository.items[*id]
+
+	if !ok {
+		return nil, nil
+	}
+
+	product := *item
+
+	return &product, nil
+}
+
+func (repository *memoryRepository) FindMany(ids []*string) ([]*Product, error) {
+	result := make([]*Product, 0)
+
+	for _, id := range ids {
+		if item, _ := repository.FindOne(id); item != nil {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (repository *memoryRepository) All() ([]*Product, error) {
+	result := make([]*Product, 0)
+
+	for id := range repository.items {
+		item, _ := repository.FindOne(&id)
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+func (repository *memoryRepository) FindByCategoryID(id *string) ([]*Product, error) {
+	result := make([]*Product, 0)
+	all, _ := repository.All()
+
+	for _, item := range all {
+		if *item.CategoryID == *id {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (repository *memoryRepository) FindByStatus(status Status) ([]*Product, error) {
+	all, _ := repository.All()
+
+	return repository.withStatus(all, status), nil
+}
+
+func (repository *memoryRepository) FindByCategoryIDAndStatus(id *string, status Status) ([]*Product, error) {
+	items, _ := repository.FindByCategoryID(id)
+
+	return repository.withStatus(items, status), nil
+}
+
+func (repository *memoryRepository) FindAvailable(categoryID *string) ([]*Product, error) {
+	result := make([]*Product, 0)
+	items, _ := repository.FindByCategoryID(categoryID)
+
+	for _, item := range items {
+		if item.IsAvailable(repository.clock.Now()) {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (repository *memoryRepository) withStatus(items []*Product, status Status) []*Product {
+	result := make([]*Product, 0)
+
+	for _, item := range items {
+		if item.CurrentStatus() == status && (status != Published || item.InWindow(repository.clock.Now())) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+func (repository *memoryRepository) FindWindowChanges(from, to time.Time) ([]*Product, error) {
+	result := make([]*Product, 0)
+	items, _ := repository.All()
+	inside := func(at *timestamp.Time) bool {
+		return at != nil && at.After(from) && !at.After(to)
+	}
+
+	for _, item := range items {
+		if inside(item.PublishAt) || inside(item.UnpublishAt) {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (repository *memoryRepository) CreatedBetween(categoryID *string, from, to time.Time) ([]*Product, error) {
+	result := make([]*Product, 0)
+	items, _ := repository.FindByCategoryID(categoryID)
+
+	for _, item := range items {
+		if !item.CreatedAt.Before(from) && !item.CreatedAt.After(to) {
+			result = append(result, item)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt.Time) })
+
+	return result, nil
+}
+
+func (repository *memoryRepository) NewestInCategory(categoryID *string, limit int64) ([]*Product, error) {
+	items, _ := repository.FindByCategoryID(categoryID)
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.After(items[j].CreatedAt.Time) })
+
+	if int64(len(items)) > limit {
+		items = items[:limit]
+	}
+
+	return items, nil
+}
+
+func (repository *memoryRepository) Delete(id *string) error {
+	if item, ok := repository.items[*id]; ok {
+		delete(repository.items, *id)
+		item.DeletedAt = timestamp.New(repository.clock.Now())
+		repository.trash[*id] = item
+	}
+
+	return nil
+}
+
+func (repository *memoryRepository) Restore(id *string) error {
+	item, ok := repository.trash[*id]
+
+	if !ok {
+		return NotFoundError{ID: *id}
+	}
+
+	delete(repository.trash, *id)
+	item.DeletedAt = nil
+	repository.items[*id] = item
+
+	return nil
+}
+
+func (repository *memoryRepository) Trash() ([]*Product, error) {
+	result := make([]*Product, 0)
+
+	for _, item := range repository.trash {
+		product := *item
+		result = append(result, &product)
+	}
+
+	return result, nil
+}
+
+func (repository *memoryRepository) FindInTrash(id *string) (*Product, error) {
+	item, ok := repository.trash[*id]
+
+	if !ok {
+		return nil, nil
+	}
+
+	product := *item
+
+	return &product, nil
+}
+
+func (repository *memoryRepository) Purge(before time.Time) ([]*Product, error) {
+	purged := make([]*Product, 0)
+
+	for id, item := range repository.trash {
+		if item.DeletedAt.Before(before) {
+			delete(repository.trash, id)
+			purged = append(purged, item)
+		}
+	}
+
+	return purged, nil
+}
+
+func (repository *memoryRepository) SetMultimedia(id *string, items multimedia.Collection) error {
+	repository.items[*id].Multimedia = items
+
+	return nil
+}
+
+// memoryCategories only implements the lookups used by the product services
+type memoryCategories struct {
+	categories.CategoryRepository
+	items map[string]*categories.Category
+}
+
+func newMemoryCategories(items ...*categories.Category) *memoryCategories {
+	repository := &memoryCategories{items: map[string]*categories.Category{}}
+
+	for _, item := range items {
+		repository.items[*item.ID] = item
+	}
+
+	return repository
+}
+
+func (repository *memoryCategories) Find(ID *string) (*categories.Category, error) {
+	return repository.items[*ID], nil
+}
+
+func (repository *memoryCategories) SubCategories(audience categories.Audience, categoryID *string) ([]*categories.Category, error) {
+	result := make([]*categories.Category, 0)
+
+	for _, item := range repository.items {
+		if item.ParentCategoryID != nil && *item.ParentCategoryID == *categoryID {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+type memoryHistory struct {
+	changes []*PriceChange
+}
+
+func (history *memoryHistory) Record(change *PriceChange) error {
+	history.changes = append(history.changes, change)
+
+	return nil
+}
+
+// changeOf tells if the change is the one of the product or the variant
+func changeOf(change *PriceChange, productID, sku *string, currency money.Currency) bool {
+	if *change.ProductID != *productID || change.Price.Currency != currency || (change.SKU == nil) != (sku == nil) {
+		return false
+	}
+
+	return sku == nil || *change.SKU == *sku
+}
+
+func (history *memoryHistory) History(productID, sku *string, currency money.Currency, from, to time.Time) ([]*PriceChange, error) {
+	result := make([]*PriceChange, 0)
+
+	for _, change := range history.changes {
+		if changeOf(change, productID, sku, currency) && !change.ChangedAt.Before(from) && !change.ChangedAt.After(to) {
+			result = append(result, change)
+		}
+	}
+
+	return result, nil
+}
+
+func (history *memoryHistory) LastBefore(productID, sku *string, currency money.Currency, at time.Time) (*PriceChange, error) {
+	var last *PriceChange
+
+	for _, change := range history.changes {
+		if changeOf(change, productID, sku, currency) && change.ChangedAt.Before(at) {
+			last = change
+		}
+	}
+
+	return last, nil
+}
+
+type memorySchedule struct {
+	items map[string]*ScheduledPriceChange
+}
+
+func (schedule *memorySchedule) Store(change *ScheduledPriceChange) error {
+	schedule.items[*change.ID] = change
+
+	return nil
+}
+
+func (schedule *memorySchedule) Find(ID *string) (*ScheduledPriceChange, error) {
+	return schedule.items[*ID], nil
+}
+
+func (schedule *memorySchedule) FindByProductID(productID *string) ([]*ScheduledPriceChange, error) {
+	result := make([]*ScheduledPriceChange, 0)
+
+	for _, item := range schedule.items {
+		if *item.ProductID == *productID {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (schedule *memorySchedule) Due(at time.Time) ([]*ScheduledPriceChange, error) {
+	result := make([]*ScheduledPriceChange, 0)
+
+	for _, item := range schedule.items {
+		if item.AppliedAt == nil && !item.EffectiveAt.After(at) {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (schedule *memorySchedule) MarkApplied(ID *string, at time.Time) error {
+	if item := schedule.items[*ID]; item.AppliedAt == nil {
+		item.AppliedAt = timestamp.New(at)
+	}
+
+	return nil
+}
+
+func (schedule *memorySchedule) Cancel(ID *string) error {
+	delete(schedule.items, *ID)
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package products
+
+import "github.com/alejo-lapix/products-go/pkg/multimedia"
+
+const MultimediaOwner = "product"
+
+// MultimediaItems returns every multimedia item used by the product and its variants
+func (product *Product) MultimediaItems() multimedia.Collection {
+	items := make(multimedia.Collection, 0, len(product.Multimedia))
+	items = append(items, product.Multimedia...)
+
+	for _, variant := range product.Variants {
+		items = append(items, variant.Multimedia...)
+	}
+
+	return items
+}
+
+// MultimediaReferences lists the multimedia used by all the products
+func MultimediaReferences(repository ProductRepository) ([]*multimedia.Reference, error) {
+	items, err := repository.All()
+
+	if err != nil {
+		return nil, err
+	}
+
+	references := make([]*multimedia.Reference, 0)
+
+	for _, item := range items {
+		references = append(references, multimedia.NewReferences(MultimediaOwner, item.ID, item.MultimediaItems())...)
+	}
+
+	return references, nil
+}
@@ -0,0 +1,123 @@
+package products
+
+import (
+	"sort"
+
+	"github.com/alejo-lapix/products-go/pkg/ordering"
+)
+
+// SortProducts sorts the list by its position inside the category,
+// the products without a position go last sorted by name
+func SortProducts(items []*Product) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return ordering.Less(sortKey(items[i]), sortKey(items[j]))
+	})
+}
+
+func sortKey(product *Product) ordering.Key {
+	return ordering.Key{Position: product.Position, Name: product.Name, ID: product.ID}
+}
+
+type OrderProductService struct {
+	Repository ProductRepository
+}
+
+func NewOrderProductService(repository ProductRepository) *OrderProductService {
+	return &OrderProductService{Repository: repository}
+}
+
+// MoveBefore places the product right before anchorID inside its category
+func (service *OrderProductService) MoveBefore(ID, anchorID *string) error {
+	return service.move(ID, anchorID, false)
+}
+
+// MoveAfter places the product right after anchorID inside its category
+func (service *OrderProductService) MoveAfter(ID, anchorID *string) error {
+	return service.move(ID, anchorID, true)
+}
+
+// SetOrder replaces the order of the products of the
+// category, the list must contain all of them
+func (service *OrderProductService) SetOrder(categoryID *string, ids []*string) error {
+	items, err := service.Repository.FindByCategoryID(categoryID)
+
+	if err != nil {
+		return err
+	}
+
+	SortProducts(items)
+
+	if err = ordering.Validate(productIDs(items), ids); err != nil {
+		return err
+	}
+
+	return service.persist(items, ids)
+}
+
+func (service *OrderProductService) move(ID, anchorID *string, after bool) error {
+	product, err := service.Repository.FindOne(ID)
+
+	if err != nil {
+		return err
+	}
+
+	if product == nil {
+		return NotFoundError{ID: *ID}
+	}
+
+	if err = checkEditable(product); err != nil {
+		return err
+	}
+
+	items, err := service.Repository.FindByCategoryID(product.CategoryID)
+
+	if err != nil {
+		return err
+	}
+
+	SortProducts(items)
+	ids, err := ordering.Move(productIDs(items), ID, anchorID, after)
+
+	if err != nil {
+		return err
+	}
+
+	return service.persist(items, ids)
+}
+
+// persist stores the new positions, only the products whose position actually
+// changed are updated, the archived products keep their stored position
+func (service *OrderProductService) persist(items []*Product, ids []*string) error {
+	byID := make(map[string]*Product, len(items))
+
+	for _, item := range items {
+		byID[*item.ID] = item
+	}
+
+	for index, id := range ids {
+		product := byID[*id]
+		position := index + 1
+
+		if (product.Position != nil && *product.Position == position) || checkEditable(product) != nil {
+			continue
+		}
+
+		product.Position = &position
+
+		if err := service.Repository.Update(product.ID, product); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func productIDs(items []*Product) []*string {
+	ids := make([]*string, len(items))
+
+	for index, item := range items {
+		ids[index] = item.ID
+	}
+
+	return ids
+}
@@ -0,0 +1,102 @@
+package products
+
+import (
+	"fmt"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/validation"
+)
+
+// PriceIn returns the price of the product in the currency, nil when it has none
+func (product *Product) PriceIn(currency money.Currency) *money.Money {
+	if product.Price != nil && product.Price.Currency == currency {
+		return product.Price
+	}
+
+	return product.Prices.In(currency)
+}
+
+// SetPrice replaces the price in the currency of the price, the
+// main price is replaced when the currencies are the same
+func (product *Product) SetPrice(price *money.Money) {
+	if product.Price == nil || product.Price.Currency == price.Currency {
+		product.Price = price
+
+		return
+	}
+
+	product.Prices.Set(price)
+}
+
+// ResolveLegacyPrices gives the currency to the prices of the product
+// and of its variants stored as plain numbers before Money existed
+func (product *Product) ResolveLegacyPrices(currency money.Currency) error {
+	if product.Price != nil {
+		if err := product.Price.ResolveLegacy(currency); err != nil {
+			return err
+		}
+	}
+
+	for _, variant := range product.Variants {
+		if variant.Price == nil {
+			continue
+		}
+
+		if err := variant.Price.ResolveLegacy(currency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkPrices rejects negative amounts and unknown currencies, Prices can not repeat
+// a currency and the variants must be priced in the currency of the main price
+func checkPrices(value interface{}) validation.Errors {
+	product := value.(*Product)
+	errs := validation.Errors{}
+	check := func(path string, price *money.Money) {
+		if price == nil {
+			return
+		}
+
+		if price.IsNegative() {
+			errs = append(errs, validation.NewFieldError(path, "gte", "must be greater than or equal to 0"))
+		}
+
+		if !price.Currency.Valid() {
+			errs = append(errs, validation.NewFieldError(path+".currency", "iso4217", "must be an ISO 4217 code"))
+		}
+	}
+
+	check("price", product.Price)
+	currencies := map[money.Currency]bool{}
+
+	if product.Price != nil {
+		currencies[product.Price.Currency] = true
+	}
+
+	for index, price := range product.Prices {
+		path := fmt.Sprintf("prices[%d]", index)
+		check(path, price)
+
+		if price != nil && currencies[price.Currency] {
+			errs = append(errs, validation.NewFieldError(path+".currency", "unique", "is repeated"))
+		}
+
+		if price != nil {
+			currencies[price.Currency] = true
+		}
+	}
+
+	for index, variant := range product.Variants {
+		path := fmt.Sprintf("variants[%d].price", index)
+		check(path, variant.Price)
+
+		if variant.Price != nil && product.Price != nil && variant.Price.Currency != product.Price.Currency {
+			errs = append(errs, validation.NewFieldError(path+".currency", "eqfield", "must be the currency of the product price"))
+		}
+	}
+
+	return errs
+}
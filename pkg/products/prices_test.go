@@ -0,0 +1,50 @@
+package products
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/validation"
+)
+
+func TestProduct_SetPrice(t *testing.T) {
+	product := &Product{ID: s("p1"), Name: s("Drill"), Price: m(10), CategoryID: s("tools")}
+	product.SetPrice(money.New(900, "EUR"))
+	product.SetPrice(m(12))
+
+	if product.PriceIn("USD").Amount != 1200 || product.PriceIn("EUR").Amount != 900 || product.PriceIn("GBP") != nil {
+		t.Errorf("SetPrice() price = %s, prices = %v", product.Price, product.Prices)
+	}
+
+	if err := Validate(product); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	product.Prices = append(product.Prices, money.New(-1, "EUR"), money.New(100, "usd"))
+	errs, _ := Validate(product).(validation.Errors)
+	want := []string{"prices[1]", "prices[1].currency", "prices[2].currency"}
+
+	if len(errs) != len(want) {
+		t.Fatalf("Validate() errors = %v, want %v", errs, want)
+	}
+
+	for index, path := range want {
+		if errs[index].Path != path {
+			t.Errorf("Validate() path = %s, want %s", errs[index].Path, path)
+		}
+	}
+}
+
+func TestProduct_ResolveLegacyPrices(t *testing.T) {
+	product := &Product{}
+	_ = json.Unmarshal([]byte(`{"id":"p1","price":5000,"variants":[{"sku":"p1-red","price":5500.5},{"sku":"p1-blue"}]}`), product)
+
+	if err := product.ResolveLegacyPrices(""); err == nil {
+		t.Errorf("ResolveLegacyPrices() error = nil, want a LegacyCurrencyError without currency")
+	}
+
+	if err := product.ResolveLegacyPrices("COP"); err != nil || product.Price.String() != "5000.00 COP" || product.Variants[0].Price.String() != "5500.50 COP" {
+		t.Errorf("ResolveLegacyPrices() price = %v, variant = %v, error = %v", product.Price, product.Variants[0].Price, err)
+	}
+}
@@ -0,0 +1,137 @@
+package products
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/rates"
+)
+
+// Ending moves the converted amounts to a psychological price, the amount goes
+// down to a multiple of Step and then Value is added, both in minor units, the
+// Ending{Step: 100, Value: 99} of USD turns 12.34 into 12.99
+type Ending struct {
+	Step  int64
+	Value int64
+}
+
+func (ending Ending) Apply(price *money.Money) *money.Money {
+	if ending.Step < 1 {
+		return price
+	}
+
+	amount := price.Amount - price.Amount%ending.Step + ending.Value
+
+	return money.New(amount, price.Currency)
+}
+
+type convertedPrice struct {
+	price     *money.Money
+	expiresAt time.Time
+}
+
+// PricingService derives the prices of the products in the currencies where they
+// do not have an explicit price, the converted amounts are kept for TTL, the
+// expired ones are evicted at most once per TTL while new prices are converted
+type PricingService struct {
+	Rates     rates.Provider
+	Endings   map[money.Currency]Ending
+	TTL       time.Duration
+	Clock     clock.Clock
+	mutex     sync.Mutex
+	cache     map[string]*convertedPrice
+	evictedAt time.Time
+}
+
+func NewPricingService(provider rates.Provider, ttl time.Duration) *PricingService {
+	return &PricingService{
+		Rates:   provider,
+		Endings: map[money.Currency]Ending{},
+		TTL:     ttl,
+		Clock:   clock.System,
+		cache:   map[string]*convertedPrice{},
+	}
+}
+
+// PriceIn returns the explicit price of the product in the currency,
+// or the conversion of its main price when it does not have one
+func (service *PricingService) PriceIn(product *Product, currency money.Currency) (*money.Money, error) {
+	if price := product.PriceIn(currency); price != nil {
+		return price, nil
+	}
+
+	if product.Price == nil {
+		return nil, fmt.Errorf("the product \"%s\" does not have a price", *product.ID)
+	}
+
+	return service.Convert(product.Price, currency)
+}
+
+// Convert rounds the amount with the money.RoundingPolicy of the
+// currency before applying the Ending configured for it
+func (service *PricingService) Convert(price *money.Money, currency money.Currency) (*money.Money, error) {
+	if price.Currency == currency {
+		return price, nil
+	}
+
+	key := fmt.Sprintf("%d %s %s", price.Amount, price.Currency, currency)
+	now := service.Clock.Now()
+
+	service.mutex.Lock()
+	cached, ok := service.cache[key]
+	service.mutex.Unlock()
+
+	if ok && now.Before(cached.expiresAt) {
+		return cached.price, nil
+	}
+
+	rate, err := service.Rates.Rate(price.Currency, currency)
+
+	if err != nil {
+		return nil, err
+	}
+
+	converted := money.FromFloat(price.Float()*rate, currency)
+
+	if ending, ok := service.Endings[currency]; ok {
+		converted = ending.Apply(converted)
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if service.cache == nil {
+		service.cache = map[string]*convertedPrice{}
+	}
+
+	service.evict(now)
+	service.cache[key] = &convertedPrice{price: converted, expiresAt: now.Add(service.TTL)}
+
+	return converted, nil
+}
+
+// evict drops the expired prices, the mutex must be locked
+func (service *PricingService) evict(now time.Time) {
+	if now.Before(service.evictedAt.Add(service.TTL)) {
+		return
+	}
+
+	for key, cached := range service.cache {
+		if !now.Before(cached.expiresAt) {
+			delete(service.cache, key)
+		}
+	}
+
+	service.evictedAt = now
+}
+
+// Flush forgets the converted prices, call it after changing the rates
+func (service *PricingService) Flush() {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.cache = map[string]*convertedPrice{}
+}
@@ -0,0 +1,87 @@
+package products
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/rates"
+)
+
+// countingRates counts the rates requested to the table
+type countingRates struct {
+	table *rates.Table
+	calls int
+}
+
+func (provider *countingRates) Rate(from, to money.Currency) (float64, error) {
+	provider.calls++
+
+	return provider.table.Rate(from, to)
+}
+
+func TestPricingService_PriceIn(t *testing.T) {
+	provider := &countingRates{table: &rates.Table{Base: "USD", Rates: map[money.Currency]float64{"EUR": 0.9, "COP": 4000}}}
+	now := clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+	service := NewPricingService(provider, time.Hour)
+	service.Clock = now
+	service.Endings["EUR"] = Ending{Step: 100, Value: 99}
+	product := &Product{ID: s("p1"), Price: m(13.5), Prices: money.Prices{money.New(1000000, "COP")}}
+
+	tests := []struct {
+		name     string
+		currency money.Currency
+		want     string
+		wantErr  bool
+	}{
+		{name: "Explicit prices are not converted", currency: "COP", want: "10000.00 COP"},
+		{name: "Converts with the ending of the currency", currency: "EUR", want: "12.99 EUR"},
+		{name: "Unknown rates", currency: "GBP", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := service.PriceIn(product, tt.currency)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PriceIn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && price.String() != tt.want {
+				t.Errorf("PriceIn() = %s, want %s", price, tt.want)
+			}
+		})
+	}
+
+	calls := provider.calls
+	_, _ = service.PriceIn(product, "EUR")
+
+	if provider.calls != calls {
+		t.Errorf("PriceIn() the converted price was not cached")
+	}
+
+	now.Advance(2 * time.Hour)
+	_, _ = service.PriceIn(product, "EUR")
+
+	if provider.calls != calls+1 {
+		t.Errorf("PriceIn() the converted price did not expire")
+	}
+}
+
+func TestPricingService_ConvertEvicts(t *testing.T) {
+	provider := &countingRates{table: &rates.Table{Base: "USD", Rates: map[money.Currency]float64{"EUR": 0.9}}}
+	now := clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+	service := NewPricingService(provider, time.Hour)
+	service.Clock = now
+
+	for amount := int64(1); amount <= 3; amount++ {
+		_, _ = service.Convert(money.New(amount*100, "USD"), "EUR")
+	}
+
+	now.Advance(2 * time.Hour)
+	_, _ = service.Convert(money.New(1000, "USD"), "EUR")
+
+	if len(service.cache) != 1 {
+		t.Errorf("Convert() cache = %d prices, want the expired ones evicted", len(service.cache))
+	}
+}
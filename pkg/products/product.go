@@ -2,48 +2,144 @@ package products
 
 import (
 	"github.com/alejo-lapix/multimedia-go/persistence"
-	"github.com/google/uuid"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+	"strings"
 	"time"
 )
 
 type UnitOfMeasurement struct {
-	Quantity *float64 `json:"quantity"`
-	Unit     *string  `json:"unit"`
+	Quantity *float64 `json:"quantity" validate:"required,gt=0"`
+	Unit     *string  `json:"unit" validate:"required"`
 }
 
 type Product struct {
-	ID                *string                       `json:"id"`
-	Name              *string                       `json:"name"`
-	Price             *float64                      `json:"price"`
-	Description       *string                       `json:"description"`
-	CategoryID        *string                       `json:"categoryId"`
-	Multimedia        []*persistence.MultimediaItem `json:"multimedia"`
-	UnitOfMeasurement *UnitOfMeasurement            `json:"unitOfMeasurement"`
-	CreatedAt         *string                       `json:"createdAt"`
+	ID                *string               `json:"id"`
+	Name              *string               `json:"name" validate:"required"`
+	Price             *money.Money          `json:"price" validate:"required"`
+	Prices            money.Prices          `json:"prices,omitempty" validate:"omitempty,dive"`
+	Description       *string               `json:"description"`
+	CategoryID        *string               `json:"categoryId" validate:"required"`
+	Multimedia        multimedia.Collection `json:"multimedia"`
+	UnitOfMeasurement *UnitOfMeasurement    `json:"unitOfMeasurement"`
+	Position          *int                  `json:"position,omitempty"`
+	Tags              []string              `json:"tags,omitempty"`
+	Options           []*OptionAxis         `json:"options,omitempty" validate:"omitempty,dive"`
+	Variants          []*Variant            `json:"variants,omitempty" validate:"omitempty,dive"`
+	Status            *Status               `json:"status,omitempty"`
+	PublishAt         *timestamp.Time       `json:"publishAt,omitempty"`
+	UnpublishAt       *timestamp.Time       `json:"unpublishAt,omitempty"`
+	CreatedAt         *timestamp.Time       `json:"createdAt"`
+	DeletedAt         *timestamp.Time       `json:"deletedAt,omitempty"`
 }
 
-func NewProductEntity(name, description, categoryID *string, price *float64, measurement *UnitOfMeasurement, multimedia []*persistence.MultimediaItem) (*Product, error) {
-	id := uuid.New().String()
-	createdAt := time.Now().Format(time.RFC3339)
+// NewProductEntity creates the product with the DefaultFactory
+func NewProductEntity(name, description, categoryID *string, price *money.Money, measurement *UnitOfMeasurement, multimedia []*persistence.MultimediaItem) (*Product, error) {
+	return DefaultFactory.NewProduct(name, description, categoryID, price, measurement, multimedia)
+}
+
+func (factory *Factory) NewProduct(name, description, categoryID *string, price *money.Money, measurement *UnitOfMeasurement, multimedia []*persistence.MultimediaItem) (*Product, error) {
+	id := factory.IDs.NewID()
+	status := Draft
 
-	return &Product{
+	product := &Product{
 		ID:                &id,
 		Name:              name,
 		Price:             price,
 		Description:       description,
 		CategoryID:        categoryID,
 		Multimedia:        multimedia,
-		CreatedAt:         &createdAt,
+		Status:            &status,
+		CreatedAt:         timestamp.New(factory.Clock.Now()),
 		UnitOfMeasurement: measurement,
-	}, nil
+	}
+
+	normalizeMeasurements(product)
+
+	if err := Validate(product); err != nil {
+		return nil, err
+	}
+
+	if err := checkUnits(product, nil); err != nil {
+		return nil, err
+	}
+
+	return product, nil
 }
 
+func (product *Product) AddMultimediaItem(item *persistence.MultimediaItem) error {
+	return product.Multimedia.Add(item)
+}
+
+func (product *Product) RemoveMultimediaItem(id *string) bool {
+	return product.Multimedia.Remove(id)
+}
+
+// ProductRepository lists the products with their variants inside,
+// the variants are never returned as products on their own
 type ProductRepository interface {
+	// Store and Update fail with DuplicateSKUError when
+	// another product already uses one of the SKUs
 	Store(*Product) error
 	Update(id *string, product *Product) error
 	FindOne(id *string) (*Product, error)
+
+	// FindBySKU returns the product with the variant that has the SKU, nil when
+	// no product has it, the deleted products keep their SKUs until purged
+	FindBySKU(sku *string) (*Product, *Variant, error)
 	FindMany(ids []*string) ([]*Product, error)
 	All() ([]*Product, error)
 	FindByCategoryID(id *string) ([]*Product, error)
+
+	// FindByStatus and FindByCategoryIDAndStatus treat the products stored without
+	// a status as published, see CurrentStatus, the published listings only have
+	// the products whose publishing window is open right now, see InWindow
+	FindByStatus(status Status) ([]*Product, error)
+	FindByCategoryIDAndStatus(id *string, status Status) ([]*Product, error)
+
+	// FindAvailable returns the products of the category that are
+	// published and whose publishing window is open right now
+	FindAvailable(categoryID *string) ([]*Product, error)
+
+	// FindWindowChanges returns the products whose publishing window
+	// opens or closes inside (from, to], whatever their status is
+	FindWindowChanges(from, to time.Time) ([]*Product, error)
+
+	// Delete only marks the product as deleted, the rest of
+	// the methods ignore it until it is restored or purged
 	Delete(id *string) error
+	Restore(id *string) error
+
+	// Trash lists the deleted products that were not purged yet
+	Trash() ([]*Product, error)
+
+	// FindInTrash returns nil when the product is not deleted
+	FindInTrash(id *string) (*Product, error)
+
+	// Purge permanently deletes the products deleted before the given time
+	Purge(before time.Time) ([]*Product, error)
+
+	// CreatedBetween returns the products of the category created
+	// inside the inclusive range, the oldest products go first
+	CreatedBetween(categoryID *string, from, to time.Time) ([]*Product, error)
+
+	// NewestInCategory returns up to limit products of the
+	// category, the most recently created products go first
+	NewestInCategory(categoryID *string, limit int64) ([]*Product, error)
+
+	// SetMultimedia only replaces the multimedia of the product,
+	// the rest of the attributes are not written
+	SetMultimedia(id *string, items multimedia.Collection) error
+}
+
+// HasTag compares the tags ignoring the case
+func (product *Product) HasTag(tag string) bool {
+	for _, current := range product.Tags {
+		if strings.EqualFold(current, tag) {
+			return true
+		}
+	}
+
+	return false
 }
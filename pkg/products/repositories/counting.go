@@ -0,0 +1,81 @@
+package repositories
+
+import "github.com/alejo-lapix/products-go/pkg/products"
+
+type counter interface {
+	ProductAdded(categoryID *string) error
+	ProductRemoved(categoryID *string) error
+	ProductMoved(fromCategoryID, toCategoryID *string) error
+}
+
+// CountingProductRepository keeps the product counters of the categories
+// up to date while the products are stored, moved, deleted or restored
+type CountingProductRepository struct {
+	products.ProductRepository
+	counter counter
+}
+
+func NewCountingProductRepository(repository products.ProductRepository, counter counter) *CountingProductRepository {
+	return &CountingProductRepository{
+		ProductRepository: repository,
+		counter:           counter,
+	}
+}
+
+func (repository *CountingProductRepository) Store(product *products.Product) error {
+	if err := repository.ProductRepository.Store(product); err != nil {
+		return err
+	}
+
+	return repository.counter.ProductAdded(product.CategoryID)
+}
+
+func (repository *CountingProductRepository) Update(id *string, product *products.Product) error {
+	current, err := repository.ProductRepository.FindOne(id)
+
+	if err != nil {
+		return err
+	}
+
+	if err = repository.ProductRepository.Update(id, product); err != nil {
+		return err
+	}
+
+	if current == nil {
+		return nil
+	}
+
+	return repository.counter.ProductMoved(current.CategoryID, product.CategoryID)
+}
+
+func (repository *CountingProductRepository) Delete(id *string) error {
+	current, err := repository.ProductRepository.FindOne(id)
+
+	if err != nil {
+		return err
+	}
+
+	if err = repository.ProductRepository.Delete(id); err != nil {
+		return err
+	}
+
+	if current == nil {
+		return nil
+	}
+
+	return repository.counter.ProductRemoved(current.CategoryID)
+}
+
+func (repository *CountingProductRepository) Restore(id *string) error {
+	if err := repository.ProductRepository.Restore(id); err != nil {
+		return err
+	}
+
+	product, err := repository.ProductRepository.FindOne(id)
+
+	if err != nil || product == nil {
+		return err
+	}
+
+	return repository.counter.ProductAdded(product.CategoryID)
+}
@@ -1,24 +1,67 @@
 package repositories
 
 import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/dynamo"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
 	"github.com/alejo-lapix/products-go/pkg/products"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 )
 
 type DynamoDBProductRepository struct {
-	DynamoDB  *dynamodb.DynamoDB
-	tableName *string
+	DynamoDB *dynamodb.DynamoDB
+	// Clock decides which publishing windows are open in FindAvailable
+	Clock clock.Clock
+	// LegacyCurrency is given to the prices stored as plain numbers before
+	// Money existed, reading one of them fails while it is empty
+	LegacyCurrency money.Currency
+
+	// Retention lets the TTL of the table purge the deleted products, see
+	// dynamo.SoftDelete and ReleaseExpired before setting it
+	Retention     time.Duration
+	tableName     *string
+	skusTableName *string
 }
 
 func NewDynamoDBProductRepository(db *dynamodb.DynamoDB) *DynamoDBProductRepository {
 	return &DynamoDBProductRepository{
-		DynamoDB:  db,
-		tableName: aws.String("products"),
+		DynamoDB:      db,
+		Clock:         clock.System,
+		tableName:     aws.String("products"),
+		skusTableName: aws.String("product-skus"),
+	}
+}
+
+func (repository *DynamoDBProductRepository) now() time.Time {
+	if repository.Clock == nil {
+		return clock.System.Now()
+	}
+
+	return repository.Clock.Now()
+}
+
+// resolve gives the legacy currency to the prices stored as plain numbers
+func (repository *DynamoDBProductRepository) resolve(items ...*products.Product) error {
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+
+		if err := item.ResolveLegacyPrices(repository.LegacyCurrency); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
+// Store reserves the SKUs of the variants in the same transaction
 func (repository *DynamoDBProductRepository) Store(product *products.Product) error {
 	item, err := dynamodbattribute.MarshalMap(product)
 
@@ -26,15 +69,35 @@ func (repository *DynamoDBProductRepository) Store(product *products.Product) er
 		return err
 	}
 
-	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+	put := &dynamodb.Put{
 		ConditionExpression: aws.String("attribute_not_exists(id)"),
 		Item:                item,
 		TableName:           repository.tableName,
-	})
+	}
+	skus := product.SKUs()
 
-	return err
+	if len(skus) == 0 {
+		_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+			ConditionExpression: put.ConditionExpression,
+			Item:                put.Item,
+			TableName:           put.TableName,
+		})
+
+		return err
+	}
+
+	transaction := []*dynamodb.TransactWriteItem{{Put: put}}
+
+	for _, sku := range skus {
+		transaction = append(transaction, repository.reserveSKU(sku, product.ID))
+	}
+
+	_, err = repository.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{TransactItems: transaction})
+
+	return skuError(err, skus)
 }
 
+// Update only touches the SKUs table when the SKUs of the variants changed
 func (repository *DynamoDBProductRepository) Update(id *string, product *products.Product) error {
 	item, err := dynamodbattribute.MarshalMap(product)
 
@@ -42,16 +105,62 @@ func (repository *DynamoDBProductRepository) Update(id *string, product *product
 		return err
 	}
 
-	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
-		ConditionExpression:       aws.String("id = :id"),
+	current, err := repository.findOne(id)
+
+	if err != nil {
+		return err
+	}
+
+	put := &dynamodb.Put{
+		ConditionExpression:       aws.String("id = :id AND " + dynamo.NotDeleted),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":id": {S: id}},
 		Item:                      item,
 		TableName:                 repository.tableName,
-	})
+	}
+	var added, removed []string
 
-	return err
+	if current != nil {
+		added, removed = difference(current.SKUs(), product.SKUs())
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+			ConditionExpression:       put.ConditionExpression,
+			ExpressionAttributeValues: put.ExpressionAttributeValues,
+			Item:                      put.Item,
+			TableName:                 put.TableName,
+		})
+
+		return err
+	}
+
+	transaction := []*dynamodb.TransactWriteItem{{Put: put}}
+
+	for _, sku := range added {
+		transaction = append(transaction, repository.reserveSKU(sku, id))
+	}
+
+	for _, sku := range removed {
+		transaction = append(transaction, repository.releaseSKU(sku, id))
+	}
+
+	_, err = repository.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{TransactItems: transaction})
+
+	return skuError(err, added)
 }
+
 func (repository *DynamoDBProductRepository) FindOne(ID *string) (*products.Product, error) {
+	product, err := repository.findOne(ID)
+
+	if err != nil || product == nil || product.DeletedAt != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// findOne also returns the deleted products
+func (repository *DynamoDBProductRepository) findOne(ID *string) (*products.Product, error) {
 	item := &products.Product{}
 	output, err := repository.DynamoDB.GetItem(&dynamodb.GetItemInput{
 		Key:       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
@@ -62,39 +171,40 @@ func (repository *DynamoDBProductRepository) FindOne(ID *string) (*products.Prod
 		return nil, err
 	}
 
+	if output.Item == nil {
+		return nil, nil
+	}
+
 	err = dynamodbattribute.UnmarshalMap(output.Item, item)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err = repository.resolve(item); err != nil {
+		return nil, err
+	}
+
 	return item, nil
 }
 
 func (repository *DynamoDBProductRepository) batchRequest(key string, items []*string) ([]*products.Product, error) {
-	list := make([]*products.Product, len(items))
+	list := make([]*products.Product, 0, len(items))
 	keys := make([]map[string]*dynamodb.AttributeValue, len(items))
 
 	for index, item := range items {
 		keys[index] = map[string]*dynamodb.AttributeValue{key: {S: item}}
 	}
 
-	output, err := repository.DynamoDB.BatchGetItem(&dynamodb.BatchGetItemInput{
-		RequestItems:           map[string]*dynamodb.KeysAndAttributes{*repository.tableName: {Keys: keys}},
-		ReturnConsumedCapacity: nil,
-	})
-
-	if err != nil {
+	if err := dynamo.BatchGet(repository.DynamoDB, repository.tableName, keys, &list); err != nil {
 		return nil, err
 	}
 
-	err = dynamodbattribute.UnmarshalListOfMaps(output.Responses[*repository.tableName], &list)
-
-	if err != nil {
+	if err := repository.resolve(list...); err != nil {
 		return nil, err
 	}
 
-	return list, nil
+	return withoutDeleted(list), nil
 }
 
 func (repository *DynamoDBProductRepository) FindMany(ids []*string) ([]*products.Product, error) {
@@ -103,49 +213,200 @@ func (repository *DynamoDBProductRepository) FindMany(ids []*string) ([]*product
 
 func (repository *DynamoDBProductRepository) FindByCategoryID(ID *string) ([]*products.Product, error) {
 	items := make([]*products.Product, 0)
-	output, err := repository.DynamoDB.Query(&dynamodb.QueryInput{
+	input := &dynamodb.QueryInput{
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":categoryId": {S: ID}},
 		KeyConditionExpression:    aws.String("categoryId = :categoryId"),
+		FilterExpression:          aws.String(dynamo.NotDeleted),
 		IndexName:                 aws.String("categoryId-index"),
 		TableName:                 repository.tableName,
-	})
+	}
 
-	if err != nil {
+	if err := dynamo.Query(repository.DynamoDB, input, 0, &items); err != nil {
 		return nil, err
 	}
 
-	err = dynamodbattribute.UnmarshalListOfMaps(output.Items, &items)
+	if err := repository.resolve(items...); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
+	products.SortProducts(items)
+
+	return items, nil
+}
+
+func (repository *DynamoDBProductRepository) FindByStatus(status products.Status) ([]*products.Product, error) {
+	items := make([]*products.Product, 0)
+	input := &dynamodb.ScanInput{TableName: repository.tableName}
+	input.FilterExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues = repository.statusFilter(status)
+
+	if err := dynamo.Scan(repository.DynamoDB, input, &items); err != nil {
+		return nil, err
+	}
+
+	if err := repository.resolve(items...); err != nil {
 		return nil, err
 	}
 
 	return items, nil
 }
 
-func (repository *DynamoDBProductRepository) Delete(ID *string) error {
-	_, err := repository.DynamoDB.DeleteItem(&dynamodb.DeleteItemInput{
-		Key:       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
-		TableName: repository.tableName,
-	})
+func (repository *DynamoDBProductRepository) FindByCategoryIDAndStatus(ID *string, status products.Status) ([]*products.Product, error) {
+	items := make([]*products.Product, 0)
+	input := &dynamodb.QueryInput{
+		KeyConditionExpression: aws.String("categoryId = :categoryId"),
+		IndexName:              aws.String("categoryId-index"),
+		TableName:              repository.tableName,
+	}
+	input.FilterExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues = repository.statusFilter(status)
+	input.ExpressionAttributeValues[":categoryId"] = &dynamodb.AttributeValue{S: ID}
 
-	return err
+	if err := dynamo.Query(repository.DynamoDB, input, 0, &items); err != nil {
+		return nil, err
+	}
+
+	if err := repository.resolve(items...); err != nil {
+		return nil, err
+	}
+
+	products.SortProducts(items)
+
+	return items, nil
 }
 
-func (repository *DynamoDBProductRepository) All() ([]*products.Product, error) {
+// FindAvailable is the published listing of the category, the variants are not
+// checked by DynamoDB, use Product.IsAvailable to discard the sold out products
+func (repository *DynamoDBProductRepository) FindAvailable(categoryID *string) ([]*products.Product, error) {
+	return repository.FindByCategoryIDAndStatus(categoryID, products.Published)
+}
+
+// FindWindowChanges reads every page of the table, the windows are stored
+// as sortable strings so DynamoDB filters the boundaries inside the range
+func (repository *DynamoDBProductRepository) FindWindowChanges(from, to time.Time) ([]*products.Product, error) {
 	items := make([]*products.Product, 0)
-	scanInput := &dynamodb.ScanInput{TableName: repository.tableName}
-	output, err := repository.DynamoDB.Scan(scanInput)
+	input := &dynamodb.ScanInput{TableName: repository.tableName}
+	input.FilterExpression, input.ExpressionAttributeValues = windowFilter(from, to)
 
-	if err != nil {
+	if err := dynamo.Scan(repository.DynamoDB, input, &items); err != nil {
 		return nil, err
 	}
 
-	err = dynamodbattribute.UnmarshalListOfMaps(output.Items, &items)
+	if err := repository.resolve(items...); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
+	return items, nil
+}
+
+func windowFilter(from, to time.Time) (*string, map[string]*dynamodb.AttributeValue) {
+	expression := dynamo.NotDeleted +
+		" AND ((publishAt > :from AND publishAt <= :to) OR (unpublishAt > :from AND unpublishAt <= :to))"
+	values := map[string]*dynamodb.AttributeValue{
+		":from": {S: aws.String(timestamp.Format(from))},
+		":to":   {S: aws.String(timestamp.Format(to))},
+	}
+
+	return aws.String(expression), values
+}
+
+// statusFilter only keeps the published products whose publishing window is open at the time
+// of the clock, the windows are stored as sortable strings so DynamoDB compares them
+func (repository *DynamoDBProductRepository) statusFilter(status products.Status) (*string, map[string]*string, map[string]*dynamodb.AttributeValue) {
+	expression := dynamo.NotDeleted + " AND #status = :status"
+	values := map[string]*dynamodb.AttributeValue{":status": {S: aws.String(string(status))}}
+
+	if status == products.Published {
+		expression = dynamo.NotDeleted + " AND (#status = :status OR attribute_not_exists(#status))" +
+			" AND (attribute_not_exists(publishAt) OR publishAt <= :now)" +
+			" AND (attribute_not_exists(unpublishAt) OR unpublishAt > :now)"
+		values[":now"] = &dynamodb.AttributeValue{S: aws.String(timestamp.Format(repository.now()))}
+	}
+
+	return aws.String(expression), map[string]*string{"#status": aws.String("status")}, values
+}
+
+// CreatedBetween uses the categoryId-createdAt-index, whose sort key is the createdAt
+// attribute stored with the sortable timestamp.Layout, run BackfillTimestamps once on
+// the tables with products stored before, their createdAt does not sort with the rest
+func (repository *DynamoDBProductRepository) CreatedBetween(categoryID *string, from, to time.Time) ([]*products.Product, error) {
+	return repository.queryCreatedAt(&dynamodb.QueryInput{
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":categoryId": {S: categoryID},
+			":from":       {S: aws.String(timestamp.Format(from))},
+			":to":         {S: aws.String(timestamp.Format(to))},
+		},
+		KeyConditionExpression: aws.String("categoryId = :categoryId AND createdAt BETWEEN :from AND :to"),
+		ScanIndexForward:       aws.Bool(true),
+	}, 0)
+}
+
+// NewestInCategory keeps reading the index until it collects limit products
+// that are not deleted, it has the same requirement of CreatedBetween
+func (repository *DynamoDBProductRepository) NewestInCategory(categoryID *string, limit int64) ([]*products.Product, error) {
+	return repository.queryCreatedAt(&dynamodb.QueryInput{
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":categoryId": {S: categoryID}},
+		KeyConditionExpression:    aws.String("categoryId = :categoryId"),
+		Limit:                     aws.Int64(limit),
+		ScanIndexForward:          aws.Bool(false),
+	}, int(limit))
+}
+
+func (repository *DynamoDBProductRepository) queryCreatedAt(input *dynamodb.QueryInput, limit int) ([]*products.Product, error) {
+	items := make([]*products.Product, 0)
+	input.FilterExpression = aws.String(dynamo.NotDeleted)
+	input.IndexName = aws.String("categoryId-createdAt-index")
+	input.TableName = repository.tableName
+
+	if err := dynamo.Query(repository.DynamoDB, input, limit, &items); err != nil {
+		return nil, err
+	}
+
+	if err := repository.resolve(items...); err != nil {
 		return nil, err
 	}
 
 	return items, nil
 }
+
+// BackfillTimestamps rewrites the createdAt of the products stored with time.RFC3339
+// before timestamp.Time existed, run it once before relying on the createdAt index
+func (repository *DynamoDBProductRepository) BackfillTimestamps() (int, error) {
+	return dynamo.BackfillTimestamps(repository.DynamoDB, repository.tableName, "id", "createdAt")
+}
+
+func (repository *DynamoDBProductRepository) All() ([]*products.Product, error) {
+	items := make([]*products.Product, 0)
+	input := &dynamodb.ScanInput{FilterExpression: aws.String(dynamo.NotDeleted), TableName: repository.tableName}
+
+	if err := dynamo.Scan(repository.DynamoDB, input, &items); err != nil {
+		return nil, err
+	}
+
+	if err := repository.resolve(items...); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// MultimediaOwners reads the multimedia of the products and their variants, the deleted ones included
+func (repository *DynamoDBProductRepository) MultimediaOwners() *multimedia.DynamoDBOwners {
+	return multimedia.NewDynamoDBOwners(repository.DynamoDB, *repository.tableName, "multimedia", "variants")
+}
+
+func (repository *DynamoDBProductRepository) SetMultimedia(id *string, items multimedia.Collection) error {
+	value, err := dynamodbattribute.Marshal(items)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.DynamoDB.UpdateItem(&dynamodb.UpdateItemInput{
+		ConditionExpression:       aws.String("attribute_exists(id)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":multimedia": value},
+		Key:                       map[string]*dynamodb.AttributeValue{"id": {S: id}},
+		TableName:                 repository.tableName,
+		UpdateExpression:          aws.String("SET multimedia = :multimedia"),
+	})
+
+	return err
+}
@@ -1,13 +1,17 @@
 package repositories
 
 import (
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/money"
 	"github.com/alejo-lapix/products-go/pkg/products"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/google/uuid"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var tableName = "products"
@@ -36,7 +40,7 @@ func TestProductRepository_Store(t *testing.T) {
 			args: args{product: &products.Product{
 				ID:          aws.String(ID),
 				Name:        aws.String("Name"),
-				Price:       aws.Float64(5000),
+				Price:       money.New(500000, "COP"),
 				Description: aws.String("Description"),
 				CategoryID:  aws.String("aaaaa"),
 				Multimedia:  nil,
@@ -80,7 +84,7 @@ func TestProductRepository_Update(t *testing.T) {
 			args: args{product: &products.Product{
 				ID:                aws.String(ID),
 				Name:              aws.String("bbbbb"),
-				Price:             aws.Float64(6000),
+				Price:             money.New(600000, "COP"),
 				Description:       aws.String("New Descriptoin"),
 				CategoryID:        aws.String("bbbbb"),
 				Multimedia:        nil,
@@ -260,3 +264,62 @@ func dynamoDBInstance() *dynamodb.DynamoDB {
 
 	return dynamodb.New(sess)
 }
+
+func TestProductRepository_statusFilter(t *testing.T) {
+	now := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	repository := &DynamoDBProductRepository{Clock: clock.NewFixedClock(now)}
+	tests := []struct {
+		name       string
+		status     products.Status
+		expression string
+		values     map[string]string
+	}{
+		{
+			name:   "Published products inside their window",
+			status: products.Published,
+			expression: "attribute_not_exists(deletedAt) AND (#status = :status OR attribute_not_exists(#status))" +
+				" AND (attribute_not_exists(publishAt) OR publishAt <= :now)" +
+				" AND (attribute_not_exists(unpublishAt) OR unpublishAt > :now)",
+			values: map[string]string{":status": "published", ":now": timestamp.Format(now)},
+		},
+		{
+			name:       "Drafts ignore the window",
+			status:     products.Draft,
+			expression: "attribute_not_exists(deletedAt) AND #status = :status",
+			values:     map[string]string{":status": "draft"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, names, values := repository.statusFilter(tt.status)
+
+			if *expression != tt.expression || *names["#status"] != "status" {
+				t.Errorf("statusFilter() = %s, names = %v, want %s", *expression, names, tt.expression)
+			}
+
+			if len(values) != len(tt.values) {
+				t.Fatalf("statusFilter() values = %v, want %v", values, tt.values)
+			}
+
+			for name, value := range tt.values {
+				if values[name] == nil || *values[name].S != value {
+					t.Errorf("statusFilter() %s = %v, want %s", name, values[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestWindowFilter(t *testing.T) {
+	from, to := time.Date(2019, 12, 1, 9, 0, 0, 0, time.UTC), time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	expression, values := windowFilter(from, to)
+	want := "attribute_not_exists(deletedAt) AND ((publishAt > :from AND publishAt <= :to) OR (unpublishAt > :from AND unpublishAt <= :to))"
+
+	if *expression != want {
+		t.Errorf("windowFilter() = %s, want %s", *expression, want)
+	}
+
+	if *values[":from"].S != timestamp.Format(from) || *values[":to"].S != timestamp.Format(to) {
+		t.Errorf("windowFilter() values = %v, want the sortable timestamps of the range", values)
+	}
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/products"
+)
+
+type recorder interface {
+	Record(change *products.PriceChange) error
+}
+
+// PriceHistoryProductRepository records the price changes of
+// the products while they are stored or updated
+type PriceHistoryProductRepository struct {
+	products.ProductRepository
+	Clock    clock.Clock
+	recorder recorder
+}
+
+func NewPriceHistoryProductRepository(repository products.ProductRepository, recorder recorder) *PriceHistoryProductRepository {
+	return &PriceHistoryProductRepository{
+		ProductRepository: repository,
+		Clock:             clock.System,
+		recorder:          recorder,
+	}
+}
+
+func (repository *PriceHistoryProductRepository) Store(product *products.Product) error {
+	if err := repository.ProductRepository.Store(product); err != nil {
+		return err
+	}
+
+	return repository.record(nil, product)
+}
+
+func (repository *PriceHistoryProductRepository) Update(id *string, product *products.Product) error {
+	current, err := repository.ProductRepository.FindOne(id)
+
+	if err != nil {
+		return err
+	}
+
+	if err = repository.ProductRepository.Update(id, product); err != nil {
+		return err
+	}
+
+	return repository.record(current, product)
+}
+
+func (repository *PriceHistoryProductRepository) record(previous, next *products.Product) error {
+	for _, change := range products.PriceChanges(previous, next, repository.Clock.Now()) {
+		if err := repository.recorder.Record(change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
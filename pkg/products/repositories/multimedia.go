@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
+	"github.com/alejo-lapix/products-go/pkg/products"
+)
+
+type releaser interface {
+	Release(items multimedia.Collection) error
+}
+
+// MultimediaProductRepository releases the multimedia of the products once they
+// are purged, the deleted products keep it in case they are restored
+type MultimediaProductRepository struct {
+	products.ProductRepository
+	releaser releaser
+}
+
+func NewMultimediaProductRepository(repository products.ProductRepository, releaser releaser) *MultimediaProductRepository {
+	return &MultimediaProductRepository{
+		ProductRepository: repository,
+		releaser:          releaser,
+	}
+}
+
+// Purge releases the multimedia of every purged product in one call, so the owners are read once
+func (repository *MultimediaProductRepository) Purge(before time.Time) ([]*products.Product, error) {
+	purged, err := repository.ProductRepository.Purge(before)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(multimedia.Collection, 0)
+
+	for _, item := range purged {
+		items = append(items, item.MultimediaItems()...)
+	}
+
+	if len(items) == 0 {
+		return purged, nil
+	}
+
+	if err = repository.releaser.Release(items); err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/dynamo"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/products"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// DynamoDBPriceHistoryRepository uses productId as the partition key and currencyChangedAt,
+// the currency, the SKU of the variant when there is one and the sortable time, as the sort key
+type DynamoDBPriceHistoryRepository struct {
+	DynamoDB  *dynamodb.DynamoDB
+	tableName *string
+}
+
+func NewDynamoDBPriceHistoryRepository(db *dynamodb.DynamoDB) *DynamoDBPriceHistoryRepository {
+	return &DynamoDBPriceHistoryRepository{
+		DynamoDB:  db,
+		tableName: aws.String("product-price-history"),
+	}
+}
+
+// priceScope prefixes the sort keys of the product price or of a variant, the sortable
+// times start with a digit, so the keys of the variants never fall in the product ranges
+func priceScope(currency money.Currency, sku *string) string {
+	if sku == nil {
+		return string(currency) + "#"
+	}
+
+	return string(currency) + "#sku#" + *sku + "#"
+}
+
+func priceKey(currency money.Currency, sku *string, at time.Time) *string {
+	return aws.String(priceScope(currency, sku) + timestamp.Format(at))
+}
+
+func (repository *DynamoDBPriceHistoryRepository) Record(change *products.PriceChange) error {
+	item, err := dynamodbattribute.MarshalMap(change)
+
+	if err != nil {
+		return err
+	}
+
+	item["currencyChangedAt"] = &dynamodb.AttributeValue{S: priceKey(change.Price.Currency, change.SKU, change.ChangedAt.Time)}
+	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+		Item:      item,
+		TableName: repository.tableName,
+	})
+
+	return err
+}
+
+func (repository *DynamoDBPriceHistoryRepository) History(productID, sku *string, currency money.Currency, from, to time.Time) ([]*products.PriceChange, error) {
+	return repository.query(&dynamodb.QueryInput{
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":productId": {S: productID},
+			":from":      {S: priceKey(currency, sku, from)},
+			":to":        {S: priceKey(currency, sku, to)},
+		},
+		KeyConditionExpression: aws.String("productId = :productId AND currencyChangedAt BETWEEN :from AND :to"),
+		ScanIndexForward:       aws.Bool(true),
+	}, 0)
+}
+
+func (repository *DynamoDBPriceHistoryRepository) LastBefore(productID, sku *string, currency money.Currency, at time.Time) (*products.PriceChange, error) {
+	changes, err := repository.query(&dynamodb.QueryInput{
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":productId": {S: productID},
+			":currency":  {S: aws.String(priceScope(currency, sku))},
+			":at":        {S: priceKey(currency, sku, at.Add(-time.Millisecond))},
+		},
+		KeyConditionExpression: aws.String("productId = :productId AND currencyChangedAt BETWEEN :currency AND :at"),
+		Limit:                  aws.Int64(1),
+		ScanIndexForward:       aws.Bool(false),
+	}, 1)
+
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+
+	return changes[0], nil
+}
+
+func (repository *DynamoDBPriceHistoryRepository) query(input *dynamodb.QueryInput, limit int) ([]*products.PriceChange, error) {
+	items := make([]*products.PriceChange, 0)
+	input.TableName = repository.tableName
+
+	if err := dynamo.Query(repository.DynamoDB, input, limit, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// pending marks the changes not applied yet, it is the partition key of the sparse
+// pending-effectiveAt-index, MarkApplied removes it so the index only holds what
+// ApplyDue still has to do and Due never reads the applied changes
+const pending = "pending"
+
+type DynamoDBScheduledPriceRepository struct {
+	DynamoDB  *dynamodb.DynamoDB
+	tableName *string
+}
+
+func NewDynamoDBScheduledPriceRepository(db *dynamodb.DynamoDB) *DynamoDBScheduledPriceRepository {
+	return &DynamoDBScheduledPriceRepository{
+		DynamoDB:  db,
+		tableName: aws.String("product-scheduled-prices"),
+	}
+}
+
+func (repository *DynamoDBScheduledPriceRepository) Store(change *products.ScheduledPriceChange) error {
+	item, err := dynamodbattribute.MarshalMap(change)
+
+	if err != nil {
+		return err
+	}
+
+	if change.AppliedAt == nil {
+		item[pending] = &dynamodb.AttributeValue{S: aws.String(pending)}
+	}
+
+	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+		Item:                item,
+		TableName:           repository.tableName,
+	})
+
+	return err
+}
+
+func (repository *DynamoDBScheduledPriceRepository) Find(ID *string) (*products.ScheduledPriceChange, error) {
+	output, err := repository.DynamoDB.GetItem(&dynamodb.GetItemInput{
+		Key:       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+		TableName: repository.tableName,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	change := &products.ScheduledPriceChange{}
+
+	if err = dynamodbattribute.UnmarshalMap(output.Item, change); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+func (repository *DynamoDBScheduledPriceRepository) FindByProductID(productID *string) ([]*products.ScheduledPriceChange, error) {
+	items := make([]*products.ScheduledPriceChange, 0)
+	err := dynamo.Query(repository.DynamoDB, &dynamodb.QueryInput{
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":productId": {S: productID}},
+		KeyConditionExpression:    aws.String("productId = :productId"),
+		IndexName:                 aws.String("productId-index"),
+		TableName:                 repository.tableName,
+	}, 0, &items)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (repository *DynamoDBScheduledPriceRepository) Due(at time.Time) ([]*products.ScheduledPriceChange, error) {
+	items := make([]*products.ScheduledPriceChange, 0)
+	err := dynamo.Query(repository.DynamoDB, &dynamodb.QueryInput{
+		ExpressionAttributeNames: map[string]*string{"#pending": aws.String(pending)},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pending": {S: aws.String(pending)},
+			":at":      {S: aws.String(timestamp.Format(at))},
+		},
+		IndexName:              aws.String("pending-effectiveAt-index"),
+		KeyConditionExpression: aws.String("#pending = :pending AND effectiveAt <= :at"),
+		TableName:              repository.tableName,
+	}, 0, &items)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (repository *DynamoDBScheduledPriceRepository) MarkApplied(ID *string, at time.Time) error {
+	_, err := repository.DynamoDB.UpdateItem(&dynamodb.UpdateItemInput{
+		ConditionExpression:       aws.String("attribute_exists(id) AND attribute_not_exists(appliedAt)"),
+		ExpressionAttributeNames:  map[string]*string{"#pending": aws.String(pending)},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":appliedAt": {S: aws.String(timestamp.Format(at))}},
+		Key:                       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+		TableName:                 repository.tableName,
+		UpdateExpression:          aws.String("SET appliedAt = :appliedAt REMOVE #pending"),
+	})
+
+	if dynamo.ConditionFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (repository *DynamoDBScheduledPriceRepository) Cancel(ID *string) error {
+	_, err := repository.DynamoDB.DeleteItem(&dynamodb.DeleteItemInput{
+		ConditionExpression: aws.String("attribute_not_exists(appliedAt)"),
+		Key:                 map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+		TableName:           repository.tableName,
+	})
+
+	if dynamo.ConditionFailed(err) {
+		return fmt.Errorf("the price change \"%s\" was already applied", *ID)
+	}
+
+	return err
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"github.com/alejo-lapix/products-go/pkg/dynamo"
+	"github.com/alejo-lapix/products-go/pkg/products"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// The SKUs table keeps one item per SKU with the ID of the product that
+// uses it, it is written in the same transaction of the product so two
+// products can never share a SKU, DynamoDB limits the transactions to
+// 25 items so a product can not add more than 24 SKUs at once
+
+// reserveSKU does not fail when the product already has the SKU
+func (repository *DynamoDBProductRepository) reserveSKU(sku string, productID *string) *dynamodb.TransactWriteItem {
+	return &dynamodb.TransactWriteItem{Put: &dynamodb.Put{
+		ConditionExpression:       aws.String("attribute_not_exists(id) OR productId = :productId"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":productId": {S: productID}},
+		Item: map[string]*dynamodb.AttributeValue{
+			"id":        {S: aws.String(sku)},
+			"productId": {S: productID},
+		},
+		TableName: repository.skusTableName,
+	}}
+}
+
+func (repository *DynamoDBProductRepository) releaseSKU(sku string, productID *string) *dynamodb.TransactWriteItem {
+	return &dynamodb.TransactWriteItem{Delete: &dynamodb.Delete{
+		ConditionExpression:       aws.String("attribute_not_exists(id) OR productId = :productId"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":productId": {S: productID}},
+		Key:                       map[string]*dynamodb.AttributeValue{"id": {S: aws.String(sku)}},
+		TableName:                 repository.skusTableName,
+	}}
+}
+
+// deleteSKU releases the SKU outside of a transaction
+func (repository *DynamoDBProductRepository) deleteSKU(sku string, productID *string) error {
+	release := repository.releaseSKU(sku, productID).Delete
+	_, err := repository.DynamoDB.DeleteItem(&dynamodb.DeleteItemInput{
+		ConditionExpression:       release.ConditionExpression,
+		ExpressionAttributeValues: release.ExpressionAttributeValues,
+		Key:                       release.Key,
+		TableName:                 release.TableName,
+	})
+
+	if dynamo.ConditionFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+// skuError translates the cancellation of the transaction caused by the SKUs
+// table into a DuplicateSKUError, the reserved SKUs must follow the product
+func skuError(err error, reserved []string) error {
+	if index := dynamo.CancelledBy(err); index > 0 && index <= len(reserved) {
+		return products.DuplicateSKUError{SKU: reserved[index-1]}
+	}
+
+	return err
+}
+
+// difference returns the SKUs that only the next list
+// has and the ones that only the current list has
+func difference(current, next []string) (added, removed []string) {
+	inCurrent := map[string]bool{}
+	inNext := map[string]bool{}
+
+	for _, sku := range current {
+		inCurrent[sku] = true
+	}
+
+	for _, sku := range next {
+		inNext[sku] = true
+
+		if !inCurrent[sku] {
+			added = append(added, sku)
+		}
+	}
+
+	for _, sku := range current {
+		if !inNext[sku] {
+			removed = append(removed, sku)
+		}
+	}
+
+	return added, removed
+}
+
+func (repository *DynamoDBProductRepository) FindBySKU(sku *string) (*products.Product, *products.Variant, error) {
+	output, err := repository.DynamoDB.GetItem(&dynamodb.GetItemInput{
+		Key:       map[string]*dynamodb.AttributeValue{"id": {S: sku}},
+		TableName: repository.skusTableName,
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	productID, ok := output.Item["productId"]
+
+	if !ok {
+		return nil, nil, nil
+	}
+
+	product, err := repository.FindOne(productID.S)
+
+	if err != nil || product == nil {
+		return nil, nil, err
+	}
+
+	variant := product.Variant(*sku)
+
+	if variant == nil {
+		return nil, nil, nil
+	}
+
+	return product, variant, nil
+}
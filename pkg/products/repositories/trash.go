@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/dynamo"
+	"github.com/alejo-lapix/products-go/pkg/products"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+func withoutDeleted(items []*products.Product) []*products.Product {
+	result := make([]*products.Product, 0, len(items))
+
+	for _, item := range items {
+		if item != nil && item.DeletedAt == nil {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// Delete does nothing when the product does not exist or is already deleted
+func (repository *DynamoDBProductRepository) Delete(ID *string) error {
+	expression, values := dynamo.SoftDelete(repository.now(), repository.Retention)
+	_, err := repository.DynamoDB.UpdateItem(&dynamodb.UpdateItemInput{
+		ConditionExpression:       aws.String("attribute_exists(id) AND " + dynamo.NotDeleted),
+		ExpressionAttributeValues: values,
+		Key:                       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+		TableName:                 repository.tableName,
+		UpdateExpression:          expression,
+	})
+
+	if dynamo.ConditionFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (repository *DynamoDBProductRepository) Restore(ID *string) error {
+	product, err := repository.findOne(ID)
+
+	if err != nil {
+		return err
+	}
+
+	if product == nil {
+		return products.NotFoundError{ID: *ID}
+	}
+
+	_, err = repository.DynamoDB.UpdateItem(&dynamodb.UpdateItemInput{
+		ConditionExpression: aws.String("attribute_exists(deletedAt)"),
+		Key:                 map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+		TableName:           repository.tableName,
+		UpdateExpression:    aws.String(dynamo.Undelete),
+	})
+
+	if dynamo.ConditionFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (repository *DynamoDBProductRepository) Trash() ([]*products.Product, error) {
+	items := make([]*products.Product, 0)
+
+	if err := dynamo.Trash(repository.DynamoDB, repository.tableName, &items); err != nil {
+		return nil, err
+	}
+
+	if err := repository.resolve(items...); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (repository *DynamoDBProductRepository) FindInTrash(ID *string) (*products.Product, error) {
+	product, err := repository.findOne(ID)
+
+	if err != nil || product == nil || product.DeletedAt == nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Purge skips the products restored while it runs
+func (repository *DynamoDBProductRepository) Purge(before time.Time) ([]*products.Product, error) {
+	trash, err := repository.Trash()
+
+	if err != nil {
+		return nil, err
+	}
+
+	purged := make([]*products.Product, 0)
+
+	for _, item := range trash {
+		if !item.DeletedAt.Before(before) {
+			continue
+		}
+
+		deleted, err := dynamo.Purge(repository.DynamoDB, repository.tableName, map[string]*dynamodb.AttributeValue{"id": {S: item.ID}})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !deleted {
+			continue
+		}
+
+		for _, sku := range item.SKUs() {
+			if err = repository.deleteSKU(sku, item.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		purged = append(purged, item)
+	}
+
+	return purged, nil
+}
+
+// ReleaseExpired releases the SKUs of the products removed by the TTL of the table, the
+// TTL never runs Purge, so call it with the records of the stream of the table when
+// Retention is set and release the multimedia of the expired products it returns
+func (repository *DynamoDBProductRepository) ReleaseExpired(records []*dynamodbstreams.Record) ([]*products.Product, error) {
+	items := make([]*products.Product, 0)
+
+	if err := dynamo.Expired(records, &items); err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		for _, sku := range item.SKUs() {
+			if err := repository.deleteSKU(sku, item.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// DynamoDBWatermark keeps the last tick of a scheduler, every
+// scheduler running at the same time needs its own name
+type DynamoDBWatermark struct {
+	DynamoDB  *dynamodb.DynamoDB
+	Name      *string
+	tableName *string
+}
+
+func NewDynamoDBWatermark(db *dynamodb.DynamoDB, name string) *DynamoDBWatermark {
+	return &DynamoDBWatermark{
+		DynamoDB:  db,
+		Name:      aws.String(name),
+		tableName: aws.String("scheduler-watermarks"),
+	}
+}
+
+func (watermark *DynamoDBWatermark) Last() (*time.Time, error) {
+	output, err := watermark.DynamoDB.GetItem(&dynamodb.GetItemInput{
+		ConsistentRead: aws.Bool(true),
+		Key:            map[string]*dynamodb.AttributeValue{"name": {S: watermark.Name}},
+		TableName:      watermark.tableName,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Item == nil || output.Item["at"] == nil || output.Item["at"].S == nil {
+		return nil, nil
+	}
+
+	at, err := timestamp.Parse(*output.Item["at"].S)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &at.Time, nil
+}
+
+func (watermark *DynamoDBWatermark) Save(at time.Time) error {
+	_, err := watermark.DynamoDB.PutItem(&dynamodb.PutItemInput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"name": {S: watermark.Name},
+			"at":   {S: aws.String(timestamp.Format(at))},
+		},
+		TableName: watermark.tableName,
+	})
+
+	return err
+}
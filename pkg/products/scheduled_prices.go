@@ -0,0 +1,188 @@
+package products
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/ids"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+)
+
+// ScheduledPriceChange replaces the price of the product in the currency of Price, or the
+// one of the variant when SKU is set, once EffectiveAt is reached, AppliedAt is set then
+type ScheduledPriceChange struct {
+	ID          *string         `json:"id"`
+	ProductID   *string         `json:"productId"`
+	SKU         *string         `json:"sku,omitempty"`
+	Price       *money.Money    `json:"price"`
+	EffectiveAt *timestamp.Time `json:"effectiveAt"`
+	AppliedAt   *timestamp.Time `json:"appliedAt,omitempty"`
+}
+
+type ScheduledPriceRepository interface {
+	Store(change *ScheduledPriceChange) error
+	Find(ID *string) (*ScheduledPriceChange, error)
+	FindByProductID(productID *string) ([]*ScheduledPriceChange, error)
+
+	// Due returns the changes not applied yet whose EffectiveAt is not after the given time
+	Due(at time.Time) ([]*ScheduledPriceChange, error)
+
+	// MarkApplied keeps the first time when the change was already applied
+	MarkApplied(ID *string, at time.Time) error
+
+	// Cancel only removes the changes that were not applied yet
+	Cancel(ID *string) error
+}
+
+// PriceScheduleService applies the scheduled price changes through the
+// product repository, decorate it with the price history to record them
+type PriceScheduleService struct {
+	Products ProductRepository
+	Changes  ScheduledPriceRepository
+	IDs      ids.Generator
+	Clock    clock.Clock
+}
+
+func NewPriceScheduleService(products ProductRepository, changes ScheduledPriceRepository) *PriceScheduleService {
+	return &PriceScheduleService{
+		Products: products,
+		Changes:  changes,
+		IDs:      ids.UUIDGenerator{},
+		Clock:    clock.System,
+	}
+}
+
+// Schedule only accepts changes in the future for products that exist
+func (service *PriceScheduleService) Schedule(productID *string, price *money.Money, effectiveAt time.Time) (*ScheduledPriceChange, error) {
+	return service.ScheduleVariant(productID, nil, price, effectiveAt)
+}
+
+// ScheduleVariant changes the price of the variant instead, the variants
+// are priced in the currency of the main price of the product
+func (service *PriceScheduleService) ScheduleVariant(productID, sku *string, price *money.Money, effectiveAt time.Time) (*ScheduledPriceChange, error) {
+	if !effectiveAt.After(service.Clock.Now()) {
+		return nil, fmt.Errorf("the price change must take effect in the future")
+	}
+
+	if price.IsNegative() || !price.Currency.Valid() {
+		return nil, fmt.Errorf("the price %s is not valid", price)
+	}
+
+	product, err := service.Products.FindOne(productID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, NotFoundError{ID: *productID}
+	}
+
+	if sku != nil {
+		if product.Variant(*sku) == nil {
+			return nil, fmt.Errorf("the product \"%s\" does not have the variant \"%s\"", *productID, *sku)
+		}
+
+		if product.Price != nil && price.Currency != product.Price.Currency {
+			return nil, fmt.Errorf("the variants must be priced in %s", product.Price.Currency)
+		}
+	}
+
+	id := service.IDs.NewID()
+	change := &ScheduledPriceChange{
+		ID:          &id,
+		ProductID:   productID,
+		SKU:         sku,
+		Price:       price,
+		EffectiveAt: timestamp.New(effectiveAt),
+	}
+
+	if err = service.Changes.Store(change); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+func (service *PriceScheduleService) Cancel(ID *string) error {
+	return service.Changes.Cancel(ID)
+}
+
+// ApplyDue applies the changes that reached their time, the oldest go first, the
+// changes of products or variants that do not exist anymore are skipped, the ones of
+// archived products stay pending until the product is restored, a change
+// is marked as applied after updating the product, applying it again after
+// a failure sets the same price so the history does not record it twice
+func (service *PriceScheduleService) ApplyDue() ([]*ScheduledPriceChange, error) {
+	now := service.Clock.Now()
+	due, err := service.Changes.Due(now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].EffectiveAt.Before(due[j].EffectiveAt.Time)
+	})
+
+	applied := make([]*ScheduledPriceChange, 0, len(due))
+
+	for _, change := range due {
+		product, err := service.Products.FindOne(change.ProductID)
+
+		if err != nil {
+			return applied, err
+		}
+
+		if product != nil && checkEditable(product) != nil {
+			continue
+		}
+
+		if product != nil && change.SKU != nil && product.Variant(*change.SKU) == nil {
+			product = nil
+		}
+
+		if product != nil {
+			if change.SKU != nil {
+				product.Variant(*change.SKU).Price = change.Price
+			} else {
+				product.SetPrice(change.Price)
+			}
+
+			if err = service.Products.Update(product.ID, product); err != nil {
+				return applied, err
+			}
+		}
+
+		if err = service.Changes.MarkApplied(change.ID, now); err != nil {
+			return applied, err
+		}
+
+		if product != nil {
+			change.AppliedAt = timestamp.New(now)
+			applied = append(applied, change)
+		}
+	}
+
+	return applied, nil
+}
+
+// Run applies the due changes every interval until stop is closed, the errors are sent to onError
+func (service *PriceScheduleService) Run(interval time.Duration, stop <-chan struct{}, onError func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := service.ApplyDue(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
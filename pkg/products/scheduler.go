@@ -0,0 +1,168 @@
+package products
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+)
+
+type WindowEventType string
+
+const (
+	WindowOpened WindowEventType = "opened"
+	WindowClosed WindowEventType = "closed"
+)
+
+// WindowEvent tells that the publishing window of the product opened or closed at the given time
+type WindowEvent struct {
+	Type      WindowEventType
+	ProductID *string
+	At        time.Time
+}
+
+// Watermark keeps the time of the last tick out of the process, Last
+// returns nil when the scheduler never ticked with this watermark
+type Watermark interface {
+	Last() (*time.Time, error)
+	Save(at time.Time) error
+}
+
+// Scheduler finds the publishing windows that opened or closed since
+// its previous tick and sends the events to the listeners
+type Scheduler struct {
+	Repository ProductRepository
+	Clock      clock.Clock
+	// Watermark is optional, without it a restarted scheduler
+	// misses the windows that changed while it was stopped
+	Watermark Watermark
+	listeners []func(event *WindowEvent)
+	last      *time.Time
+}
+
+// NewScheduler only reports the windows that change after its creation
+func NewScheduler(repository ProductRepository, clock clock.Clock) *Scheduler {
+	now := clock.Now()
+
+	return &Scheduler{
+		Repository: repository,
+		Clock:      clock,
+		last:       &now,
+	}
+}
+
+// NewPersistentScheduler resumes from the last tick saved in the watermark, so the
+// windows that changed while it was stopped are reported on its first tick, the
+// events may be sent twice when saving the watermark fails after sending them
+func NewPersistentScheduler(repository ProductRepository, clock clock.Clock, watermark Watermark) *Scheduler {
+	return &Scheduler{
+		Repository: repository,
+		Clock:      clock,
+		Watermark:  watermark,
+	}
+}
+
+func (scheduler *Scheduler) Subscribe(listener func(event *WindowEvent)) {
+	scheduler.listeners = append(scheduler.listeners, listener)
+}
+
+// Tick emits the events of the windows that changed in (previous tick, now],
+// the events are sorted by time, when it fails the same period is checked again
+func (scheduler *Scheduler) Tick() ([]*WindowEvent, error) {
+	now := scheduler.Clock.Now()
+
+	if err := scheduler.resume(now); err != nil {
+		return nil, err
+	}
+
+	items, err := scheduler.Repository.FindWindowChanges(*scheduler.last, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*WindowEvent, 0)
+
+	for _, item := range items {
+		if !item.IsPublished() {
+			continue
+		}
+
+		if item.PublishAt != nil && scheduler.changed(item.PublishAt.Time, now) {
+			events = append(events, &WindowEvent{Type: WindowOpened, ProductID: item.ID, At: item.PublishAt.Time})
+		}
+
+		if item.UnpublishAt != nil && scheduler.changed(item.UnpublishAt.Time, now) {
+			events = append(events, &WindowEvent{Type: WindowClosed, ProductID: item.ID, At: item.UnpublishAt.Time})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At)
+	})
+
+	for _, event := range events {
+		for _, listener := range scheduler.listeners {
+			listener(event)
+		}
+	}
+
+	if scheduler.Watermark != nil {
+		if err := scheduler.Watermark.Save(now); err != nil {
+			return events, err
+		}
+	}
+
+	scheduler.last = &now
+
+	return events, nil
+}
+
+// resume reads the watermark before the first tick, the first
+// tick of a new watermark only reports the changes after it
+func (scheduler *Scheduler) resume(now time.Time) error {
+	if scheduler.last != nil {
+		return nil
+	}
+
+	if scheduler.Watermark == nil {
+		scheduler.last = &now
+
+		return nil
+	}
+
+	last, err := scheduler.Watermark.Last()
+
+	if err != nil {
+		return err
+	}
+
+	if last == nil {
+		last = &now
+	}
+
+	scheduler.last = last
+
+	return nil
+}
+
+func (scheduler *Scheduler) changed(at, now time.Time) bool {
+	return at.After(*scheduler.last) && !at.After(now)
+}
+
+// Run ticks every interval until stop is closed, the errors are sent to onError
+func (scheduler *Scheduler) Run(interval time.Duration, stop <-chan struct{}, onError func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := scheduler.Tick(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package products
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+)
+
+func at(hour int) time.Time {
+	return time.Date(2019, 12, 1, hour, 0, 0, 0, time.UTC)
+}
+
+func scheduled(ID string, status Status, publishAt, unpublishAt int) *Product {
+	product := &Product{ID: s(ID), Name: s(ID), Price: m(1), CategoryID: s("tools"), Status: &status}
+
+	if publishAt >= 0 {
+		product.PublishAt = timestamp.New(at(publishAt))
+	}
+
+	if unpublishAt >= 0 {
+		product.UnpublishAt = timestamp.New(at(unpublishAt))
+	}
+
+	return product
+}
+
+func TestProduct_IsAvailable(t *testing.T) {
+	tests := []struct {
+		name    string
+		product *Product
+		want    bool
+	}{
+		{name: "Published without a window", product: scheduled("p1", Published, -1, -1), want: true},
+		{name: "Drafts are never available", product: scheduled("p1", Draft, -1, -1)},
+		{name: "Before the window opens", product: scheduled("p1", Published, 11, -1)},
+		{name: "When the window opens", product: scheduled("p1", Published, 10, 12), want: true},
+		{name: "When the window closes", product: scheduled("p1", Published, 8, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.product.IsAvailable(at(10)); got != tt.want {
+				t.Errorf("IsAvailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductService_Schedule(t *testing.T) {
+	repository := newMemoryRepository(scheduled("p1", Published, -1, -1))
+	service := NewProductService(repository, catalog())
+	service.Factory = testFactory()
+	opens, closes := at(10), at(8)
+
+	if _, err := service.Schedule(s("p1"), &opens, &closes); err == nil {
+		t.Errorf("Schedule() expected an error when the window closes before it opens")
+	}
+
+	closes = at(12)
+	product, err := service.Schedule(s("p1"), &opens, &closes)
+
+	if err != nil || !product.PublishAt.Equal(opens) || !repository.items["p1"].UnpublishAt.Equal(closes) {
+		t.Errorf("Schedule() product = %v, error = %v", product, err)
+	}
+
+	repository.clock = clock.NewFixedClock(at(9))
+
+	if items, _ := repository.FindAvailable(s("tools")); len(items) != 0 {
+		t.Errorf("FindAvailable() = %d products before the window opens", len(items))
+	}
+}
+
+func TestScheduler_Tick(t *testing.T) {
+	repository := newMemoryRepository(
+		scheduled("launch", Published, 10, -1),
+		scheduled("season", Published, 8, 11),
+		scheduled("draft", Draft, 10, -1),
+	)
+	now := clock.NewFixedClock(at(9))
+	scheduler := NewScheduler(repository, now)
+	received := make([]*WindowEvent, 0)
+	scheduler.Subscribe(func(event *WindowEvent) {
+		received = append(received, event)
+	})
+
+	ticks := []struct {
+		hour int
+		want []string
+	}{
+		{hour: 9, want: []string{}},
+		{hour: 10, want: []string{"opened launch"}},
+		{hour: 12, want: []string{"closed season"}},
+		{hour: 13, want: []string{}},
+	}
+	for _, tick := range ticks {
+		now.Set(at(tick.hour))
+		events, err := scheduler.Tick()
+
+		if err != nil || len(events) != len(tick.want) {
+			t.Fatalf("Tick() at %d events = %v, error = %v", tick.hour, events, err)
+		}
+
+		for index, event := range events {
+			if got := string(event.Type) + " " + *event.ProductID; got != tick.want[index] {
+				t.Errorf("Tick() at %d event = %s, want %s", tick.hour, got, tick.want[index])
+			}
+		}
+	}
+
+	if len(received) != 2 {
+		t.Errorf("Subscribe() received %d events, want 2", len(received))
+	}
+}
+
+func TestMemoryRepository_FindByStatus(t *testing.T) {
+	repository := newMemoryRepository(
+		scheduled("open", Published, 8, 12),
+		scheduled("later", Published, 11, -1),
+		scheduled("closed", Published, 8, 10),
+		scheduled("draft", Draft, 11, -1),
+	)
+	repository.clock = clock.NewFixedClock(at(10))
+
+	if items, _ := repository.FindByStatus(Published); len(items) != 1 || *items[0].ID != "open" {
+		t.Errorf("FindByStatus() = %v, want only the open window", items)
+	}
+
+	if items, _ := repository.FindByCategoryIDAndStatus(s("tools"), Published); len(items) != 1 {
+		t.Errorf("FindByCategoryIDAndStatus() = %d products, want 1", len(items))
+	}
+
+	if items, _ := repository.FindByStatus(Draft); len(items) != 1 {
+		t.Errorf("FindByStatus() = %d drafts, the window only applies to the published products", len(items))
+	}
+}
+
+type memoryWatermark struct {
+	at *time.Time
+}
+
+func (watermark *memoryWatermark) Last() (*time.Time, error) {
+	return watermark.at, nil
+}
+
+func (watermark *memoryWatermark) Save(at time.Time) error {
+	watermark.at = &at
+
+	return nil
+}
+
+func TestScheduler_TickAfterRestart(t *testing.T) {
+	repository := newMemoryRepository(
+		scheduled("launch", Published, 10, -1),
+		scheduled("season", Published, 8, 11),
+	)
+	now := clock.NewFixedClock(at(9))
+	watermark := &memoryWatermark{}
+
+	if events, err := NewPersistentScheduler(repository, now, watermark).Tick(); err != nil || len(events) != 0 {
+		t.Fatalf("Tick() events = %v, error = %v", events, err)
+	}
+
+	// The scheduler was stopped while both windows changed
+	now.Set(at(12))
+	events, err := NewPersistentScheduler(repository, now, watermark).Tick()
+
+	if err != nil || len(events) != 2 || *events[0].ProductID != "launch" || *events[1].ProductID != "season" {
+		t.Errorf("Tick() after the restart events = %v, error = %v", events, err)
+	}
+
+	if !watermark.at.Equal(at(12)) {
+		t.Errorf("Tick() saved the watermark %v, want %v", watermark.at, at(12))
+	}
+}
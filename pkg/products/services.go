@@ -1,18 +1,59 @@
 package products
 
-import "github.com/alejo-lapix/multimedia-go/persistence"
+import (
+	"fmt"
+	"time"
+
+	"github.com/alejo-lapix/multimedia-go/persistence"
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+)
 
 type ProductService struct {
 	Repository ProductRepository
+	Categories categories.CategoryRepository
+	Factory    *Factory
+}
+
+func NewProductService(repository ProductRepository, categoryRepository categories.CategoryRepository) *ProductService {
+	return &ProductService{
+		Repository: repository,
+		Categories: categoryRepository,
+		Factory:    DefaultFactory,
+	}
 }
 
-func (service *ProductService) NewProduct(name, description, categoryID *string, price *float64, measurement *UnitOfMeasurement, multimedia []*persistence.MultimediaItem) (*Product, error) {
-	product, err := NewProductEntity(name, description, categoryID, price, measurement, multimedia)
+func (service *ProductService) factory() *Factory {
+	if service.Factory == nil {
+		return DefaultFactory
+	}
+
+	return service.Factory
+}
+
+// ProductInput has the attributes needed to create a product
+type ProductInput struct {
+	Name              *string                       `json:"name"`
+	Description       *string                       `json:"description"`
+	CategoryID        *string                       `json:"categoryId"`
+	Price             *money.Money                  `json:"price"`
+	UnitOfMeasurement *UnitOfMeasurement            `json:"unitOfMeasurement"`
+	Multimedia        []*persistence.MultimediaItem `json:"multimedia"`
+}
+
+func (service *ProductService) NewProduct(name, description, categoryID *string, price *money.Money, measurement *UnitOfMeasurement, multimedia []*persistence.MultimediaItem) (*Product, error) {
+	product, err := service.factory().NewProduct(name, description, categoryID, price, measurement, multimedia)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err = service.checkCategory(product.CategoryID); err != nil {
+		return nil, err
+	}
+
 	err = service.Repository.Store(product)
 
 	if err != nil {
@@ -21,3 +62,329 @@ func (service *ProductService) NewProduct(name, description, categoryID *string,
 
 	return product, nil
 }
+
+// NewProducts validates all the products before storing any of them, when some of them
+// are not valid a BulkError is returned, when storing one of them fails the products
+// already stored are returned with a PartialStoreError
+func (service *ProductService) NewProducts(inputs []*ProductInput) ([]*Product, error) {
+	list := make([]*Product, len(inputs))
+	errs := BulkError{}
+	checked := map[string]error{}
+
+	for index, input := range inputs {
+		product, err := service.factory().NewProduct(input.Name, input.Description, input.CategoryID, input.Price, input.UnitOfMeasurement, input.Multimedia)
+
+		if err != nil {
+			errs[index] = err
+			continue
+		}
+
+		categoryErr, ok := checked[*product.CategoryID]
+
+		if !ok {
+			categoryErr = service.checkCategory(product.CategoryID)
+			checked[*product.CategoryID] = categoryErr
+		}
+
+		if categoryErr != nil {
+			errs[index] = categoryErr
+			continue
+		}
+
+		list[index] = product
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	for index, product := range list {
+		if err := service.Repository.Store(product); err != nil {
+			stored := list[:index]
+			partial := PartialStoreError{Index: index, Stored: make([]*string, len(stored)), Err: err}
+
+			for position, item := range stored {
+				partial.Stored[position] = item.ID
+			}
+
+			return stored, partial
+		}
+	}
+
+	return list, nil
+}
+
+func (service *ProductService) FindProduct(id *string) (*Product, error) {
+	if err := service.factory().CheckID(id); err != nil {
+		return nil, err
+	}
+
+	product, err := service.Repository.FindOne(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, NotFoundError{ID: *id}
+	}
+
+	return product, nil
+}
+
+// UpdateProduct replaces the attributes of the product, the ID, the creation
+// date, the status and the publishing window of the stored product are kept,
+// archived products can not be updated, use the lifecycle methods and
+// Schedule to change the status and the window
+func (service *ProductService) UpdateProduct(id *string, product *Product) (*Product, error) {
+	current, err := service.FindProduct(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if current.IsArchived() {
+		return nil, ArchivedProductError{ID: *id}
+	}
+
+	product.ID = current.ID
+	product.CreatedAt = current.CreatedAt
+	product.Status = current.Status
+	product.PublishAt = current.PublishAt
+	product.UnpublishAt = current.UnpublishAt
+	normalizeMeasurements(product)
+
+	if err = Validate(product); err != nil {
+		return nil, err
+	}
+
+	if err = checkUnits(product, current); err != nil {
+		return nil, err
+	}
+
+	// The products stored before the category was required may not have one
+	if current.CategoryID == nil || *product.CategoryID != *current.CategoryID {
+		if err = service.checkCategory(product.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = service.Repository.Update(id, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (service *ProductService) MoveToCategory(id, categoryID *string) (*Product, error) {
+	product, err := service.FindProduct(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	product.CategoryID = categoryID
+	// The position belongs to the previous category
+	product.Position = nil
+
+	return service.UpdateProduct(id, product)
+}
+
+// AddVariant fails with validation.Errors when the variant does not match the
+// option axes of the product and with DuplicateSKUError when its SKU is in use
+func (service *ProductService) AddVariant(id *string, variant *Variant) (*Product, error) {
+	product, err := service.editable(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = product.AddVariant(variant); err != nil {
+		return nil, err
+	}
+
+	if err = service.Repository.Update(id, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// RemoveVariant releases the SKU of the variant, so other product can use it
+func (service *ProductService) RemoveVariant(id *string, sku string) (*Product, error) {
+	product, err := service.editable(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !product.RemoveVariant(sku) {
+		return nil, fmt.Errorf("the product \"%s\" does not have the SKU \"%s\"", *id, sku)
+	}
+
+	if err = service.Repository.Update(id, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// editable finds the product, the archived ones can not be edited
+func (service *ProductService) editable(id *string) (*Product, error) {
+	product, err := service.FindProduct(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkEditable(product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func checkEditable(product *Product) error {
+	if product.IsArchived() {
+		return ArchivedProductError{ID: *product.ID}
+	}
+
+	return nil
+}
+
+// SetMultimedia only replaces the multimedia of the product
+func (service *ProductService) SetMultimedia(id *string, items multimedia.Collection) (*Product, error) {
+	product, err := service.editable(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = service.Repository.SetMultimedia(id, items); err != nil {
+		return nil, err
+	}
+
+	product.Multimedia = items
+
+	return product, nil
+}
+
+// Publish makes a draft product live
+func (service *ProductService) Publish(id *string) (*Product, error) {
+	return service.changeStatus(id, "", Published)
+}
+
+// Unpublish takes a published product back to draft
+func (service *ProductService) Unpublish(id *string) (*Product, error) {
+	return service.changeStatus(id, Published, Draft)
+}
+
+// Archive retires a draft or published product, it can not be edited until it is restored
+func (service *ProductService) Archive(id *string) (*Product, error) {
+	return service.changeStatus(id, "", Archived)
+}
+
+// Restore takes an archived product back to draft
+func (service *ProductService) Restore(id *string) (*Product, error) {
+	return service.changeStatus(id, Archived, Draft)
+}
+
+// Schedule replaces the publishing window of the product,
+// a nil time leaves that side of the window open
+func (service *ProductService) Schedule(id *string, publishAt, unpublishAt *time.Time) (*Product, error) {
+	product, err := service.editable(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	product.PublishAt = nil
+	product.UnpublishAt = nil
+
+	if publishAt != nil {
+		product.PublishAt = timestamp.New(*publishAt)
+	}
+
+	if unpublishAt != nil {
+		product.UnpublishAt = timestamp.New(*unpublishAt)
+	}
+
+	if err = Validate(product); err != nil {
+		return nil, err
+	}
+
+	if err = service.Repository.Update(id, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// changeStatus applies the transition, when from is not empty
+// the product must currently have that status
+func (service *ProductService) changeStatus(id *string, from, to Status) (*Product, error) {
+	product, err := service.FindProduct(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if from != "" && product.CurrentStatus() != from {
+		return nil, InvalidTransitionError{ID: *id, From: product.CurrentStatus(), To: to}
+	}
+
+	if err = product.Transition(to); err != nil {
+		return nil, err
+	}
+
+	if err = service.Repository.Update(id, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (service *ProductService) DeleteProduct(id *string) error {
+	if _, err := service.FindProduct(id); err != nil {
+		return err
+	}
+
+	return service.Repository.Delete(id)
+}
+
+func (service *ProductService) checkCategory(categoryID *string) error {
+	return checkCategory(service.Categories, categoryID)
+}
+
+// checkCategory only accepts categories that exist and are effectively visible
+func checkCategory(repository categories.CategoryRepository, categoryID *string) error {
+	if repository == nil {
+		return fmt.Errorf("the categories repository is required to check the category of the product")
+	}
+
+	if categoryID == nil || *categoryID == "" {
+		return fmt.Errorf("the product does not have a category")
+	}
+
+	category, err := repository.Find(categoryID)
+
+	if err != nil {
+		return err
+	}
+
+	if category == nil {
+		return CategoryUnavailableError{ID: *categoryID, Reason: "does not exist"}
+	}
+
+	visible, err := categories.IsEffectivelyVisible(repository, categoryID)
+
+	if err != nil {
+		return err
+	}
+
+	if !visible {
+		return CategoryUnavailableError{ID: *categoryID, Reason: "is not visible"}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package products
+
+import (
+	"testing"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+)
+
+func catalog() *memoryCategories {
+	return newMemoryCategories(
+		&categories.Category{ID: s("tools"), Visible: b(true)},
+		&categories.Category{ID: s("hidden"), Visible: b(false)},
+		&categories.Category{ID: s("garden"), Visible: b(true)},
+	)
+}
+
+func measurement() *UnitOfMeasurement {
+	return &UnitOfMeasurement{Quantity: f(1), Unit: s("unit")}
+}
+
+func TestProductService_NewProduct(t *testing.T) {
+	tests := []struct {
+		name       string
+		categoryID string
+		wantErr    bool
+	}{
+		{name: "Stores the product in a visible category", categoryID: "tools"},
+		{name: "Rejects unknown categories", categoryID: "unknown", wantErr: true},
+		{name: "Rejects hidden categories", categoryID: "hidden", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newMemoryRepository()
+			service := NewProductService(repository, catalog())
+			service.Factory = testFactory()
+			_, err := service.NewProduct(s("Drill"), nil, s(tt.categoryID), m(10), measurement(), nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewProduct() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, ok := err.(CategoryUnavailableError); tt.wantErr && !ok {
+				t.Errorf("NewProduct() error = %T, want CategoryUnavailableError", err)
+			}
+			if stored := len(repository.items) == 1; stored == tt.wantErr {
+				t.Errorf("NewProduct() stored = %v", stored)
+			}
+		})
+	}
+}
+
+func TestProductService_NewProducts(t *testing.T) {
+	repository := newMemoryRepository()
+	service := NewProductService(repository, catalog())
+	service.Factory = testFactory()
+
+	_, err := service.NewProducts([]*ProductInput{
+		{Name: s("Drill"), CategoryID: s("tools"), Price: m(1), UnitOfMeasurement: measurement()},
+		{Name: s("Saw"), CategoryID: s("hidden"), Price: m(1), UnitOfMeasurement: measurement()},
+		{CategoryID: s("tools"), Price: m(1)},
+	})
+
+	errs, ok := err.(BulkError)
+
+	if !ok || len(errs) != 2 || errs[1] == nil || errs[2] == nil {
+		t.Fatalf("NewProducts() error = %v", err)
+	}
+
+	if len(repository.items) != 0 {
+		t.Errorf("NewProducts() stored %d products, want 0", len(repository.items))
+	}
+}
+
+func TestProductService_NewProductsPartialStore(t *testing.T) {
+	// The second product gets the ID of a stored one, so storing it fails
+	repository := newMemoryRepository(&Product{ID: s("id-2"), Name: s("Stored"), CategoryID: s("tools")})
+	service := NewProductService(repository, catalog())
+	service.Factory = testFactory()
+
+	stored, err := service.NewProducts([]*ProductInput{
+		{Name: s("Drill"), CategoryID: s("tools"), Price: m(1), UnitOfMeasurement: measurement()},
+		{Name: s("Saw"), CategoryID: s("tools"), Price: m(1), UnitOfMeasurement: measurement()},
+	})
+
+	partial, ok := err.(PartialStoreError)
+
+	if !ok || partial.Index != 1 || len(partial.Stored) != 1 || *partial.Stored[0] != "id-1" || len(stored) != 1 {
+		t.Errorf("NewProducts() stored = %v, error = %v", stored, err)
+	}
+}
+
+func TestProductService_UpdateLegacyProduct(t *testing.T) {
+	repository := newMemoryRepository(&Product{ID: s("p1"), Name: s("Drill"), Price: m(1)})
+	service := NewProductService(repository, catalog())
+	service.Factory = testFactory()
+
+	if _, err := service.UpdateProduct(s("p1"), &Product{Name: s("Drill"), Price: m(1), CategoryID: s("tools")}); err != nil {
+		t.Errorf("UpdateProduct() error = %v, want the product without category updated", err)
+	}
+
+	service.Categories = nil
+
+	if _, err := service.UpdateProduct(s("p1"), &Product{Name: s("Drill"), Price: m(1), CategoryID: s("garden")}); err == nil {
+		t.Errorf("UpdateProduct() expected an error without the categories repository")
+	}
+}
+
+func TestProductService_MoveToCategory(t *testing.T) {
+	repository := newMemoryRepository(&Product{ID: s("p1"), Name: s("Drill"), Price: m(1), CategoryID: s("tools")})
+	service := NewProductService(repository, catalog())
+	service.Factory = testFactory()
+
+	if _, err := service.MoveToCategory(s("p1"), s("hidden")); err == nil {
+		t.Errorf("MoveToCategory() expected an error moving to a hidden category")
+	}
+
+	product, err := service.MoveToCategory(s("p1"), s("garden"))
+
+	if err != nil || *product.CategoryID != "garden" || *repository.items["p1"].CategoryID != "garden" {
+		t.Errorf("MoveToCategory() product = %v, error = %v", product, err)
+	}
+
+	if err = service.DeleteProduct(s("p1")); err != nil {
+		t.Errorf("DeleteProduct() error = %v", err)
+	}
+
+	if _, ok := service.DeleteProduct(s("p1")).(NotFoundError); !ok {
+		t.Errorf("DeleteProduct() expected a NotFoundError")
+	}
+}
+
+func TestProductService_Lifecycle(t *testing.T) {
+	repository := newMemoryRepository()
+	service := NewProductService(repository, catalog())
+	service.Factory = testFactory()
+	product, err := service.NewProduct(s("Drill"), nil, s("tools"), m(10), measurement(), nil)
+
+	if err != nil || product.CurrentStatus() != Draft {
+		t.Fatalf("NewProduct() product = %v, error = %v, want a draft", product, err)
+	}
+
+	steps := []struct {
+		name    string
+		change  func(id *string) (*Product, error)
+		want    Status
+		wantErr bool
+	}{
+		{name: "Drafts can not be restored", change: service.Restore, want: Draft, wantErr: true},
+		{name: "Publishes the draft", change: service.Publish, want: Published},
+		{name: "Unpublishes the product", change: service.Unpublish, want: Draft},
+		{name: "Archives the draft", change: service.Archive, want: Archived},
+		{name: "Archived products can not be published", change: service.Publish, want: Archived, wantErr: true},
+		{name: "Archived products can not be unpublished", change: service.Unpublish, want: Archived, wantErr: true},
+		{name: "Restores the product as a draft", change: service.Restore, want: Draft},
+	}
+	for _, step := range steps {
+		_, err := step.change(product.ID)
+
+		if _, ok := err.(InvalidTransitionError); ok != step.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", step.name, err, step.wantErr)
+		}
+
+		if status := repository.items[*product.ID].CurrentStatus(); status != step.want {
+			t.Errorf("%s: status = %s, want %s", step.name, status, step.want)
+		}
+	}
+}
+
+func TestProductService_UpdateArchivedProduct(t *testing.T) {
+	archived := Archived
+	repository := newMemoryRepository(&Product{ID: s("p1"), Name: s("Drill"), Price: m(1), CategoryID: s("tools"), Status: &archived})
+	service := NewProductService(repository, catalog())
+	service.Factory = testFactory()
+
+	if _, err := service.UpdateProduct(s("p1"), &Product{Name: s("Saw"), Price: m(2), CategoryID: s("tools")}); err == nil {
+		t.Errorf("UpdateProduct() expected an ArchivedProductError")
+	} else if _, ok := err.(ArchivedProductError); !ok {
+		t.Errorf("UpdateProduct() error = %T, want ArchivedProductError", err)
+	}
+
+	if _, err := service.SetMultimedia(s("p1"), nil); err == nil {
+		t.Errorf("SetMultimedia() expected an ArchivedProductError")
+	}
+
+	if err := NewOrderProductService(repository).MoveBefore(s("p1"), s("p1")); err == nil {
+		t.Errorf("MoveBefore() expected an ArchivedProductError")
+	} else if _, ok := err.(ArchivedProductError); !ok {
+		t.Errorf("MoveBefore() error = %T, want ArchivedProductError", err)
+	}
+
+	if _, err := service.Restore(s("p1")); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	product, err := service.UpdateProduct(s("p1"), &Product{Name: s("Saw"), Price: m(2), CategoryID: s("tools")})
+
+	if err != nil || product.CurrentStatus() != Draft {
+		t.Errorf("UpdateProduct() product = %v, error = %v", product, err)
+	}
+}
+
+func TestCategoryAssignments_MoveProducts(t *testing.T) {
+	position := 2
+	repository := newMemoryRepository(&Product{ID: s("p1"), Name: s("Drill"), Price: m(1), CategoryID: s("tools"), Position: &position})
+
+	if err := NewCategoryAssignments(repository).MoveProducts([]*string{s("p1"), s("missing")}, s("other")); err != nil {
+		t.Fatalf("MoveProducts() error = %v", err)
+	}
+
+	if moved, _ := repository.FindOne(s("p1")); *moved.CategoryID != "other" || moved.Position != nil {
+		t.Errorf("MoveProducts() category = %s, position = %v, want other without position", *moved.CategoryID, moved.Position)
+	}
+}
@@ -0,0 +1,57 @@
+package products
+
+type Status string
+
+const (
+	Draft     Status = "draft"
+	Published Status = "published"
+	Archived  Status = "archived"
+)
+
+// transitions has the statuses reachable from every status
+var transitions = map[Status][]Status{
+	Draft:     {Published, Archived},
+	Published: {Draft, Archived},
+	Archived:  {Draft},
+}
+
+// CurrentStatus returns the status of the product, the products
+// stored before the lifecycle existed are considered published
+func (product *Product) CurrentStatus() Status {
+	if product.Status == nil || *product.Status == "" {
+		return Published
+	}
+
+	return *product.Status
+}
+
+func (product *Product) IsPublished() bool {
+	return product.CurrentStatus() == Published
+}
+
+func (product *Product) IsArchived() bool {
+	return product.CurrentStatus() == Archived
+}
+
+// CanTransition tells if the product can go from its current status to the given one
+func (product *Product) CanTransition(to Status) bool {
+	for _, status := range transitions[product.CurrentStatus()] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Transition changes the status of the product, it fails with
+// InvalidTransitionError when the lifecycle does not allow it
+func (product *Product) Transition(to Status) error {
+	if !product.CanTransition(to) {
+		return InvalidTransitionError{ID: *product.ID, From: product.CurrentStatus(), To: to}
+	}
+
+	product.Status = &to
+
+	return nil
+}
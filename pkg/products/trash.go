@@ -0,0 +1,61 @@
+package products
+
+import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/clock"
+)
+
+// TrashProductService restores the deleted products and purges the
+// ones that stayed deleted longer than the retention period
+type TrashProductService struct {
+	Repository ProductRepository
+	Categories categories.CategoryRepository
+	Retention  time.Duration
+	Clock      clock.Clock
+}
+
+func NewTrashProductService(repository ProductRepository, categoryRepository categories.CategoryRepository, retention time.Duration) *TrashProductService {
+	return &TrashProductService{
+		Repository: repository,
+		Categories: categoryRepository,
+		Retention:  retention,
+		Clock:      clock.System,
+	}
+}
+
+func (service *TrashProductService) Trash() ([]*Product, error) {
+	return service.Repository.Trash()
+}
+
+// Restore fails with CategoryUnavailableError when the
+// category of the product is not available anymore
+func (service *TrashProductService) Restore(id *string) (*Product, error) {
+	product, err := service.Repository.FindInTrash(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, NotFoundError{ID: *id}
+	}
+
+	if err = checkCategory(service.Categories, product.CategoryID); err != nil {
+		return nil, err
+	}
+
+	if err = service.Repository.Restore(id); err != nil {
+		return nil, err
+	}
+
+	product.DeletedAt = nil
+
+	return product, nil
+}
+
+// Purge permanently deletes the products deleted before the retention period
+func (service *TrashProductService) Purge() ([]*Product, error) {
+	return service.Repository.Purge(service.Clock.Now().Add(-service.Retention))
+}
@@ -0,0 +1,51 @@
+package products
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/clock"
+)
+
+func TestTrashProductService(t *testing.T) {
+	now := clock.NewFixedClock(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))
+	repository := newMemoryRepository(
+		&Product{ID: s("p1"), Name: s("Drill"), Price: m(1), CategoryID: s("tools")},
+		&Product{ID: s("p2"), Name: s("Saw"), Price: m(1), CategoryID: s("tools")},
+	)
+	repository.clock = now
+	catalog := newMemoryCategories(&categories.Category{ID: s("tools"), Visible: b(true)})
+	service := NewTrashProductService(repository, catalog, 7*24*time.Hour)
+	service.Clock = now
+
+	_ = repository.Delete(s("p1"))
+	now.Advance(8 * 24 * time.Hour)
+	_ = repository.Delete(s("p2"))
+
+	if found, _ := repository.FindOne(s("p1")); found != nil {
+		t.Errorf("FindOne() returned a deleted product")
+	}
+
+	catalog.items["tools"].Visible = b(false)
+
+	if _, err := service.Restore(s("p2")); err == nil {
+		t.Errorf("Restore() expected an error while the category is hidden")
+	}
+
+	catalog.items["tools"].Visible = b(true)
+
+	if product, err := service.Restore(s("p2")); err != nil || product.DeletedAt != nil {
+		t.Errorf("Restore() product = %v, error = %v", product, err)
+	}
+
+	purged, err := service.Purge()
+
+	if err != nil || len(purged) != 1 || *purged[0].ID != "p1" {
+		t.Errorf("Purge() purged = %v, error = %v, want only p1", purged, err)
+	}
+
+	if trash, _ := service.Trash(); len(trash) != 0 {
+		t.Errorf("Trash() = %v, want it empty", trash)
+	}
+}
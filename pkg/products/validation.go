@@ -0,0 +1,19 @@
+package products
+
+import "github.com/alejo-lapix/products-go/pkg/validation"
+
+var validator = validation.New(func(value interface{}) validation.Errors {
+	return validation.NotBlank("name", value.(*Product).Name)
+}, checkPrices, checkWindow, checkVariants)
+
+// RegisterRule adds a rule that is checked every time a product is validated
+func RegisterRule(rule func(product *Product) validation.Errors) {
+	validator.Register(func(value interface{}) validation.Errors {
+		return rule(value.(*Product))
+	})
+}
+
+// Validate returns validation.Errors when the product is not valid
+func Validate(product *Product) error {
+	return validator.Validate(product)
+}
@@ -0,0 +1,70 @@
+package products
+
+import (
+	"testing"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/validation"
+)
+
+func s(input string) *string {
+	return &input
+}
+
+func f(input float64) *float64 {
+	return &input
+}
+
+func m(amount float64) *money.Money {
+	return money.FromFloat(amount, "USD")
+}
+
+func TestNewProductEntity(t *testing.T) {
+	tests := []struct {
+		name        string
+		productName *string
+		price       *money.Money
+		measurement *UnitOfMeasurement
+		wantPaths   []string
+	}{
+		{
+			name:        "Valid product",
+			productName: s("Drill"),
+			price:       m(100),
+			measurement: &UnitOfMeasurement{Quantity: f(1), Unit: s("unit")},
+		},
+		{
+			name:        "Rejects nil names and negative prices",
+			price:       m(-1),
+			measurement: &UnitOfMeasurement{Quantity: f(1), Unit: s("unit")},
+			wantPaths:   []string{"name", "price"},
+		},
+		{
+			name:        "Rejects zero quantities",
+			productName: s("Drill"),
+			price:       m(1),
+			measurement: &UnitOfMeasurement{Quantity: f(0), Unit: s("kg")},
+			wantPaths:   []string{"unitOfMeasurement.quantity"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProductEntity(tt.productName, nil, s("category"), tt.price, tt.measurement, nil)
+			if (err != nil) != (len(tt.wantPaths) > 0) {
+				t.Fatalf("NewProductEntity() error = %v", err)
+			}
+			if err == nil {
+				return
+			}
+			errs := err.(validation.Errors)
+			if len(errs) != len(tt.wantPaths) {
+				t.Fatalf("NewProductEntity() errors = %v, want %v", errs, tt.wantPaths)
+			}
+			for index, path := range tt.wantPaths {
+				if errs[index].Path != path {
+					t.Errorf("NewProductEntity() path = %s, want %s", errs[index].Path, path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package products
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/multimedia"
+	"github.com/alejo-lapix/products-go/pkg/validation"
+)
+
+// OptionAxis is an attribute in which the variants of a product differ, like the size
+type OptionAxis struct {
+	Name   *string   `json:"name" validate:"required"`
+	Values []*string `json:"values" validate:"required,min=1"`
+}
+
+// Variant is a sellable version of the product with a value for every option axis,
+// the nil attributes are taken from the product, a nil Available means available
+type Variant struct {
+	SKU               *string               `json:"sku" validate:"required"`
+	Options           map[string]string     `json:"options"`
+	Price             *money.Money          `json:"price,omitempty"`
+	UnitOfMeasurement *UnitOfMeasurement    `json:"unitOfMeasurement,omitempty"`
+	Multimedia        multimedia.Collection `json:"multimedia,omitempty"`
+	Available         *bool                 `json:"available,omitempty"`
+}
+
+func (variant *Variant) IsAvailable() bool {
+	return variant.Available == nil || *variant.Available
+}
+
+// key identifies the combination of options of the variant
+func (variant *Variant) key() string {
+	names := make([]string, 0, len(variant.Options))
+
+	for name := range variant.Options {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	parts := make([]string, len(names))
+
+	for index, name := range names {
+		parts[index] = name + "=" + variant.Options[name]
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// Variant returns the variant with the given SKU, nil when the product does not have it
+func (product *Product) Variant(sku string) *Variant {
+	for _, variant := range product.Variants {
+		if variant.SKU != nil && *variant.SKU == sku {
+			return variant
+		}
+	}
+
+	return nil
+}
+
+// SKUs returns the SKUs of all the variants of the product
+func (product *Product) SKUs() []string {
+	skus := make([]string, 0, len(product.Variants))
+
+	for _, variant := range product.Variants {
+		if variant.SKU != nil {
+			skus = append(skus, *variant.SKU)
+		}
+	}
+
+	return skus
+}
+
+// FindVariant returns the variant with exactly the given options
+func (product *Product) FindVariant(options map[string]string) *Variant {
+	expected := (&Variant{Options: options}).key()
+
+	for _, variant := range product.Variants {
+		if variant.key() == expected {
+			return variant
+		}
+	}
+
+	return nil
+}
+
+// PriceOf returns the price of the variant, the price of the product when it has none
+func (product *Product) PriceOf(variant *Variant) *money.Money {
+	if variant.Price != nil {
+		return variant.Price
+	}
+
+	return product.Price
+}
+
+// PriceRange returns the lowest and the highest price among the variants, all
+// of them share the currency of the product, both are the price of the product
+// when it does not have variants
+func (product *Product) PriceRange() (min, max *money.Money) {
+	if len(product.Variants) == 0 {
+		return product.Price, product.Price
+	}
+
+	for _, variant := range product.Variants {
+		price := product.PriceOf(variant)
+
+		if price == nil {
+			continue
+		}
+
+		if min == nil || price.Amount < min.Amount {
+			min = price
+		}
+
+		if max == nil || price.Amount > max.Amount {
+			max = price
+		}
+	}
+
+	return min, max
+}
+
+// hasAvailableVariant is true for the products without variants
+func (product *Product) hasAvailableVariant() bool {
+	if len(product.Variants) == 0 {
+		return true
+	}
+
+	for _, variant := range product.Variants {
+		if variant.IsAvailable() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddVariant validates the variant against the option axes, the rest of
+// the variants and the registered units before adding it
+func (product *Product) AddVariant(variant *Variant) error {
+	variant.UnitOfMeasurement.Normalize()
+	product.Variants = append(product.Variants, variant)
+
+	path := fmt.Sprintf("variants[%d].unitOfMeasurement", len(product.Variants)-1)
+
+	if errs := append(checkVariants(product), checkUnit(path, variant.UnitOfMeasurement, nil)...); len(errs) > 0 {
+		product.Variants = product.Variants[:len(product.Variants)-1]
+
+		return errs
+	}
+
+	return nil
+}
+
+func (product *Product) RemoveVariant(sku string) bool {
+	for index, variant := range product.Variants {
+		if variant.SKU != nil && *variant.SKU == sku {
+			product.Variants = append(product.Variants[:index], product.Variants[index+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkVariants requires one valid value per option axis in every
+// variant, the SKUs and the combinations of options can not repeat
+func checkVariants(value interface{}) validation.Errors {
+	product := value.(*Product)
+	errs := validation.Errors{}
+	axes := map[string]map[string]bool{}
+
+	for _, axis := range product.Options {
+		if axis.Name == nil {
+			continue
+		}
+
+		axes[*axis.Name] = map[string]bool{}
+
+		for _, option := range axis.Values {
+			if option != nil {
+				axes[*axis.Name][*option] = true
+			}
+		}
+	}
+
+	skus := map[string]bool{}
+	combinations := map[string]bool{}
+
+	for index, variant := range product.Variants {
+		path := fmt.Sprintf("variants[%d]", index)
+
+		if variant.SKU != nil {
+			if skus[*variant.SKU] {
+				errs = append(errs, validation.NewFieldError(path+".sku", "unique", "is repeated"))
+			}
+
+			skus[*variant.SKU] = true
+		}
+
+		if len(variant.Options) != len(axes) {
+			errs = append(errs, validation.NewFieldError(path+".options", "axes", "must have one value per option axis"))
+		}
+
+		for name, option := range variant.Options {
+			if values, ok := axes[name]; !ok || !values[option] {
+				errs = append(errs, validation.NewFieldError(path+".options."+name, "oneof", fmt.Sprintf("\"%s\" is not a value of the option axis", option)))
+			}
+		}
+
+		if combinations[variant.key()] {
+			errs = append(errs, validation.NewFieldError(path+".options", "unique", "another variant has the same options"))
+		}
+
+		combinations[variant.key()] = true
+	}
+
+	return errs
+}
@@ -0,0 +1,100 @@
+package products
+
+import (
+	"testing"
+
+	"github.com/alejo-lapix/products-go/pkg/validation"
+)
+
+func shirt(ID string) *Product {
+	return &Product{
+		ID:         s(ID),
+		Name:       s("Shirt"),
+		Price:      m(20),
+		CategoryID: s("tools"),
+		Options: []*OptionAxis{
+			{Name: s("size"), Values: []*string{s("S"), s("M")}},
+			{Name: s("colour"), Values: []*string{s("red"), s("blue")}},
+		},
+	}
+}
+
+func variant(sku, size, colour string) *Variant {
+	return &Variant{SKU: s(sku), Options: map[string]string{"size": size, "colour": colour}}
+}
+
+func TestProduct_AddVariant(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant *Variant
+		wantErr bool
+	}{
+		{name: "Adds a variant with one value per axis", variant: variant("SH-M-BLUE", "M", "blue")},
+		{name: "Rejects repeated SKUs", variant: variant("SH-S-RED", "M", "blue"), wantErr: true},
+		{name: "Rejects repeated options", variant: variant("SH-S-RED-2", "S", "red"), wantErr: true},
+		{name: "Rejects unknown values", variant: variant("SH-XL-RED", "XL", "red"), wantErr: true},
+		{name: "Rejects missing axes", variant: &Variant{SKU: s("SH-S"), Options: map[string]string{"size": "S"}}, wantErr: true},
+		{name: "Rejects variants without SKU", variant: &Variant{Options: map[string]string{"size": "M", "colour": "red"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := shirt("p1")
+			product.Variants = []*Variant{variant("SH-S-RED", "S", "red")}
+			err := product.AddVariant(tt.variant)
+
+			if err == nil {
+				// The struct tags are only checked by Validate
+				err = Validate(product)
+			}
+
+			if _, ok := err.(validation.Errors); ok != tt.wantErr {
+				t.Errorf("AddVariant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProduct_PriceRange(t *testing.T) {
+	product := shirt("p1")
+	product.Variants = []*Variant{variant("SH-S-RED", "S", "red"), variant("SH-M-RED", "M", "red")}
+	product.Variants[1].Price = m(25)
+	min, max := product.PriceRange()
+
+	if min.Amount != 2000 || max.Amount != 2500 {
+		t.Errorf("PriceRange() = %s, %s, want 20.00 USD, 25.00 USD", min, max)
+	}
+
+	if found := product.FindVariant(map[string]string{"colour": "red", "size": "M"}); found == nil || *found.SKU != "SH-M-RED" {
+		t.Errorf("FindVariant() = %v, want SH-M-RED", found)
+	}
+}
+
+func TestProductService_Variants(t *testing.T) {
+	repository := newMemoryRepository(shirt("p1"), shirt("p2"))
+	service := NewProductService(repository, catalog())
+	service.Factory = testFactory()
+
+	if _, err := service.AddVariant(s("p1"), variant("SH-S-RED", "S", "red")); err != nil {
+		t.Fatalf("AddVariant() error = %v", err)
+	}
+
+	if _, err := service.AddVariant(s("p2"), variant("SH-S-RED", "S", "red")); err == nil {
+		t.Errorf("AddVariant() expected a DuplicateSKUError")
+	} else if _, ok := err.(DuplicateSKUError); !ok {
+		t.Errorf("AddVariant() error = %T, want DuplicateSKUError", err)
+	}
+
+	product, found, _ := repository.FindBySKU(s("SH-S-RED"))
+
+	if product == nil || *product.ID != "p1" || found.Options["size"] != "S" {
+		t.Errorf("FindBySKU() = %v, %v", product, found)
+	}
+
+	if _, err := service.RemoveVariant(s("p1"), "SH-S-RED"); err != nil {
+		t.Fatalf("RemoveVariant() error = %v", err)
+	}
+
+	if _, err := service.AddVariant(s("p2"), variant("SH-S-RED", "S", "red")); err != nil {
+		t.Errorf("AddVariant() error = %v, the SKU was released", err)
+	}
+}
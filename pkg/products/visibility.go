@@ -0,0 +1,59 @@
+package products
+
+import (
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/clock"
+)
+
+// ProductVisibility derives the visibility of the products from their status,
+// their publishing window and their category chain, a product is visible only
+// when it is available at the time of the clock and its category is visible
+type ProductVisibility struct {
+	Categories categories.CategoryRepository
+	Clock      clock.Clock
+}
+
+func NewProductVisibility(repository categories.CategoryRepository) *ProductVisibility {
+	return &ProductVisibility{Categories: repository, Clock: clock.System}
+}
+
+func (visibility *ProductVisibility) IsVisible(product *Product) (bool, error) {
+	if !product.IsAvailable(visibility.Clock.Now()) || product.CategoryID == nil || *product.CategoryID == "" {
+		return false, nil
+	}
+
+	return categories.IsEffectivelyVisible(visibility.Categories, product.CategoryID)
+}
+
+// Filter returns only the visible products of the list, the
+// visibility of every category is resolved only once
+func (visibility *ProductVisibility) Filter(items []*Product) ([]*Product, error) {
+	resolved := map[string]bool{}
+	result := make([]*Product, 0, len(items))
+	now := visibility.Clock.Now()
+
+	for _, item := range items {
+		if !item.IsAvailable(now) || item.CategoryID == nil {
+			continue
+		}
+
+		visible, ok := resolved[*item.CategoryID]
+
+		if !ok {
+			var err error
+			visible, err = visibility.IsVisible(item)
+
+			if err != nil {
+				return nil, err
+			}
+
+			resolved[*item.CategoryID] = visible
+		}
+
+		if visible {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
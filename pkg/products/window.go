@@ -0,0 +1,35 @@
+package products
+
+import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/validation"
+)
+
+// IsAvailable tells if the product is published and the given time is inside its
+// publishing window, the window starts at PublishAt and ends right before UnpublishAt,
+// the products with variants also need at least one available variant
+func (product *Product) IsAvailable(at time.Time) bool {
+	return product.IsPublished() && product.hasAvailableVariant() && product.InWindow(at)
+}
+
+// InWindow tells if the given time is inside the publishing window, the
+// products without PublishAt or UnpublishAt are open on that side
+func (product *Product) InWindow(at time.Time) bool {
+	if product.PublishAt != nil && at.Before(product.PublishAt.Time) {
+		return false
+	}
+
+	return product.UnpublishAt == nil || at.Before(product.UnpublishAt.Time)
+}
+
+// checkWindow rejects windows that close before they open
+func checkWindow(value interface{}) validation.Errors {
+	product := value.(*Product)
+
+	if product.PublishAt == nil || product.UnpublishAt == nil || product.PublishAt.Before(product.UnpublishAt.Time) {
+		return nil
+	}
+
+	return validation.Errors{validation.NewFieldError("unpublishAt", "after", "must be after publishAt")}
+}
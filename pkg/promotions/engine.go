@@ -0,0 +1,195 @@
+package promotions
+
+import (
+	"sort"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/products"
+)
+
+// AppliedPromotion is a promotion with the amount it discounted
+type AppliedPromotion struct {
+	Promotion *Promotion   `json:"promotion"`
+	Discount  *money.Money `json:"discount"`
+}
+
+// Quote is the price of a product after the promotions
+type Quote struct {
+	Base       *money.Money        `json:"base"`
+	Effective  *money.Money        `json:"effective"`
+	Promotions []*AppliedPromotion `json:"promotions"`
+}
+
+// Engine finds the promotions of the products and applies them
+type Engine struct {
+	Promotions PromotionRepository
+	Categories categories.CategoryRepository
+	Clock      clock.Clock
+}
+
+func NewEngine(promotions PromotionRepository, categoryRepository categories.CategoryRepository) *Engine {
+	return &Engine{
+		Promotions: promotions,
+		Categories: categoryRepository,
+		Clock:      clock.System,
+	}
+}
+
+// Price applies the promotions to the main price of the product
+func (engine *Engine) Price(product *products.Product) (*Quote, error) {
+	return engine.Apply(product, product.Price)
+}
+
+// Apply reads the active promotions for a single price, use Batch to price many products
+func (engine *Engine) Apply(product *products.Product, price *money.Money) (*Quote, error) {
+	batch, err := engine.Batch()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return batch.Apply(product, price)
+}
+
+// Batch prices many products, like the ones of a listing, with the promotions active
+// when it was created, the promotions are read once and the ancestors of every
+// category are looked up once, create a new one for every request
+type Batch struct {
+	categories categories.CategoryRepository
+	active     []*Promotion
+	chains     map[string]map[string]bool
+}
+
+func (engine *Engine) Batch() (*Batch, error) {
+	active, err := engine.Promotions.Active(engine.Clock.Now())
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].Priority != active[j].Priority {
+			return active[i].Priority > active[j].Priority
+		}
+
+		return *active[i].ID < *active[j].ID
+	})
+
+	return &Batch{categories: engine.Categories, active: active, chains: map[string]map[string]bool{}}, nil
+}
+
+// Price applies the promotions to the main price of the product
+func (batch *Batch) Price(product *products.Product) (*Quote, error) {
+	return batch.Apply(product, product.Price)
+}
+
+// Apply applies the promotions to the given price of the product, when the promotion
+// with the highest priority is exclusive it is the only one applied, otherwise all
+// the stackable promotions are applied one after the other
+func (batch *Batch) Apply(product *products.Product, price *money.Money) (*Quote, error) {
+	matching, err := batch.matching(product)
+
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &Quote{Base: price, Effective: price, Promotions: []*AppliedPromotion{}}
+
+	if price == nil {
+		return quote, nil
+	}
+
+	for _, promotion := range matching {
+		if promotion.Stacking == Exclusive && len(quote.Promotions) > 0 {
+			continue
+		}
+
+		discount := promotion.Discount(quote.Effective)
+
+		if discount == nil || discount.IsZero() {
+			continue
+		}
+
+		quote.Effective, _ = quote.Effective.Sub(discount)
+		quote.Promotions = append(quote.Promotions, &AppliedPromotion{Promotion: promotion, Discount: discount})
+
+		if promotion.Stacking == Exclusive {
+			break
+		}
+	}
+
+	return quote, nil
+}
+
+// matching keeps the promotions that target the product, the ancestors
+// of its category are only looked up when a promotion needs them
+func (batch *Batch) matching(product *products.Product) ([]*Promotion, error) {
+	var categoryIDs map[string]bool
+	result := make([]*Promotion, 0)
+
+	for _, promotion := range batch.active {
+		if len(promotion.Target.CategoryIDs) > 0 && categoryIDs == nil {
+			var err error
+			categoryIDs, err = batch.categoryChain(product)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if targets(promotion.Target, product, categoryIDs) {
+			result = append(result, promotion)
+		}
+	}
+
+	return result, nil
+}
+
+func (batch *Batch) categoryChain(product *products.Product) (map[string]bool, error) {
+	if product.CategoryID == nil {
+		return map[string]bool{}, nil
+	}
+
+	if chain, ok := batch.chains[*product.CategoryID]; ok {
+		return chain, nil
+	}
+
+	chain := map[string]bool{*product.CategoryID: true}
+	ancestors, err := categories.Ancestors(batch.categories, product.CategoryID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ancestor := range ancestors {
+		chain[*ancestor.ID] = true
+	}
+
+	batch.chains[*product.CategoryID] = chain
+
+	return chain, nil
+}
+
+func targets(target Target, product *products.Product, categoryIDs map[string]bool) bool {
+	for _, ID := range target.ProductIDs {
+		if product.ID != nil && *product.ID == ID {
+			return true
+		}
+	}
+
+	for _, ID := range target.CategoryIDs {
+		if categoryIDs[ID] {
+			return true
+		}
+	}
+
+	for _, tag := range target.Tags {
+		if product.HasTag(tag) {
+			return true
+		}
+	}
+
+	return false
+}
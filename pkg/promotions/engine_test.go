@@ -0,0 +1,168 @@
+package promotions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/products"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+)
+
+func percentage(ID string, value float64, priority int, stacking Stacking, target Target) *Promotion {
+	return &Promotion{ID: s(ID), Name: s(ID), Kind: Percentage, Percentage: f(value), Priority: priority, Stacking: stacking, Target: target}
+}
+
+func fixed(ID string, amount *money.Money, priority int, stacking Stacking, target Target) *Promotion {
+	return &Promotion{ID: s(ID), Name: s(ID), Kind: Fixed, Amount: amount, Priority: priority, Stacking: stacking, Target: target}
+}
+
+func TestEngine_Price(t *testing.T) {
+	now := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	catalog := newMemoryCategories(
+		&categories.Category{ID: s("tools")},
+		&categories.Category{ID: s("drills"), ParentCategoryID: s("tools")},
+	)
+	drill := &products.Product{ID: s("p1"), CategoryID: s("drills"), Price: money.New(10000, "USD"), Tags: []string{"Christmas"}}
+	expired := percentage("expired", 50, 9, Stackable, Target{ProductIDs: []string{"p1"}})
+	expired.EndsAt = timestamp.New(now)
+
+	tests := []struct {
+		name       string
+		promotions []*Promotion
+		want       int64
+		applied    []string
+	}{
+		{
+			name:       "Targets the ancestors of the category",
+			promotions: []*Promotion{percentage("tools", 10, 1, Stackable, Target{CategoryIDs: []string{"tools"}})},
+			want:       9000,
+			applied:    []string{"tools"},
+		},
+		{
+			name: "Stacks by priority",
+			promotions: []*Promotion{
+				fixed("fixed", money.New(1000, "USD"), 1, Stackable, Target{Tags: []string{"christmas"}}),
+				percentage("percentage", 10, 2, Stackable, Target{ProductIDs: []string{"p1"}}),
+			},
+			want:    8000,
+			applied: []string{"percentage", "fixed"},
+		},
+		{
+			name: "Exclusive promotions go alone",
+			promotions: []*Promotion{
+				percentage("exclusive", 20, 5, Exclusive, Target{ProductIDs: []string{"p1"}}),
+				percentage("stackable", 10, 1, Stackable, Target{ProductIDs: []string{"p1"}}),
+			},
+			want:    8000,
+			applied: []string{"exclusive"},
+		},
+		{
+			name: "Exclusive promotions are skipped after a stackable one",
+			promotions: []*Promotion{
+				percentage("exclusive", 20, 1, Exclusive, Target{ProductIDs: []string{"p1"}}),
+				percentage("stackable", 10, 5, Stackable, Target{ProductIDs: []string{"p1"}}),
+			},
+			want:    9000,
+			applied: []string{"stackable"},
+		},
+		{
+			name: "Skips other currencies, other products and expired promotions",
+			promotions: []*Promotion{
+				fixed("euros", money.New(1000, "EUR"), 1, Stackable, Target{ProductIDs: []string{"p1"}}),
+				percentage("other", 10, 1, Stackable, Target{ProductIDs: []string{"p2"}, CategoryIDs: []string{"garden"}}),
+				expired,
+			},
+			want:    10000,
+			applied: []string{},
+		},
+		{
+			name:       "Never goes below zero",
+			promotions: []*Promotion{fixed("fixed", money.New(50000, "USD"), 1, Stackable, Target{ProductIDs: []string{"p1"}})},
+			want:       0,
+			applied:    []string{"fixed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(newMemoryRepository(tt.promotions...), catalog)
+			engine.Clock = clock.NewFixedClock(now)
+			quote, err := engine.Price(drill)
+
+			if err != nil || quote.Effective.Amount != tt.want || len(quote.Promotions) != len(tt.applied) {
+				t.Fatalf("Price() quote = %+v, error = %v, want %d", quote, err, tt.want)
+			}
+
+			for index, applied := range quote.Promotions {
+				if *applied.Promotion.ID != tt.applied[index] {
+					t.Errorf("Price() applied %s, want %s", *applied.Promotion.ID, tt.applied[index])
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		promotion *Promotion
+		wantErr   bool
+	}{
+		{name: "Valid", promotion: percentage("p", 10, 0, Stackable, Target{Tags: []string{"sale"}})},
+		{name: "Percentages above 100", promotion: percentage("p", 110, 0, Stackable, Target{Tags: []string{"sale"}}), wantErr: true},
+		{name: "Without target", promotion: percentage("p", 10, 0, Stackable, Target{}), wantErr: true},
+		{name: "Unknown stacking", promotion: percentage("p", 10, 0, "", Target{Tags: []string{"sale"}}), wantErr: true},
+		{name: "Fixed without amount", promotion: fixed("p", nil, 0, Stackable, Target{Tags: []string{"sale"}}), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.promotion); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEngine_Batch(t *testing.T) {
+	catalog := newMemoryCategories(
+		&categories.Category{ID: s("tools")},
+		&categories.Category{ID: s("drills"), ParentCategoryID: s("tools")},
+	)
+	repository := newMemoryRepository(percentage("tools", 10, 1, Stackable, Target{CategoryIDs: []string{"tools"}}))
+	engine := NewEngine(repository, catalog)
+	batch, err := engine.Batch()
+
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+
+	for _, ID := range []string{"p1", "p2", "p3"} {
+		quote, err := batch.Price(&products.Product{ID: s(ID), CategoryID: s("drills"), Price: money.New(10000, "USD")})
+
+		if err != nil || quote.Effective.Amount != 9000 {
+			t.Fatalf("Price() quote = %+v, error = %v", quote, err)
+		}
+	}
+
+	if repository.active != 1 || catalog.finds != 2 {
+		t.Errorf("Batch() read the promotions %d times and the categories %d times, want 1 and 2", repository.active, catalog.finds)
+	}
+}
+
+func TestPromotion_DiscountIncomplete(t *testing.T) {
+	price := money.New(10000, "USD")
+
+	if discount := (&Promotion{Kind: Percentage}).Discount(price); discount != nil {
+		t.Errorf("Discount() = %v, want nil without percentage", discount)
+	}
+
+	if discount := (&Promotion{Kind: Fixed}).Discount(price); discount != nil {
+		t.Errorf("Discount() = %v, want nil without amount", discount)
+	}
+
+	if err := newMemoryRepository().Store(fixed("p", nil, 0, Stackable, Target{Tags: []string{"sale"}})); err == nil {
+		t.Errorf("Store() accepted a fixed promotion without amount")
+	}
+}
@@ -0,0 +1,106 @@
+package promotions
+
+import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/categories"
+)
+
+func s(input string) *string {
+	return &input
+}
+
+func f(input float64) *float64 {
+	return &input
+}
+
+type memoryRepository struct {
+	items  map[string]*Promotion
+	active int
+}
+
+func newMemoryRepository(items ...*Promotion) *memoryRepository {
+	repository := &memoryRepository{items: map[string]*Promotion{}}
+
+	for _, item := range items {
+		repository.items[*item.ID] = item
+	}
+
+	return repository
+}
+
+func (repository *memoryRepository) Store(promotion *Promotion) error {
+	if err := Validate(promotion); err != nil {
+		return err
+	}
+
+	repository.items[*promotion.ID] = promotion
+
+	return nil
+}
+
+func (repository *memoryRepository) Update(ID *string, promotion *Promotion) error {
+	if err := Validate(promotion); err != nil {
+		return err
+	}
+
+	repository.items[*ID] = promotion
+
+	return nil
+}
+
+func (repository *memoryRepository) Find(ID *string) (*Promotion, error) {
+	return repository.items[*ID], nil
+}
+
+func (repository *memoryRepository) Remove(ID *string) error {
+	delete(repository.items, *ID)
+
+	return nil
+}
+
+func (repository *memoryRepository) All() ([]*Promotion, error) {
+	result := make([]*Promotion, 0)
+
+	for _, item := range repository.items {
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+func (repository *memoryRepository) Active(at time.Time) ([]*Promotion, error) {
+	result := make([]*Promotion, 0)
+	repository.active++
+
+	for _, item := range repository.items {
+		if item.IsActive(at) {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+// memoryCategories only implements Find, the engine does not need more
+type memoryCategories struct {
+	categories.CategoryRepository
+	items map[string]*categories.Category
+	finds int
+}
+
+func newMemoryCategories(items ...*categories.Category) *memoryCategories {
+	repository := &memoryCategories{items: map[string]*categories.Category{}}
+
+	for _, item := range items {
+		repository.items[*item.ID] = item
+	}
+
+	return repository
+}
+
+func (repository *memoryCategories) Find(ID *string) (*categories.Category, error) {
+	repository.finds++
+
+	return repository.items[*ID], nil
+}
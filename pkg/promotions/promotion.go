@@ -0,0 +1,151 @@
+package promotions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+	"github.com/alejo-lapix/products-go/pkg/validation"
+)
+
+type Kind string
+
+const (
+	// Percentage discounts a percentage of the current price
+	Percentage Kind = "percentage"
+	// Fixed discounts an amount, only from prices of the same currency
+	Fixed Kind = "fixed"
+)
+
+type Stacking string
+
+const (
+	// Stackable promotions are combined with the other stackable promotions
+	Stackable Stacking = "stackable"
+	// Exclusive promotions are never combined with other promotions
+	Exclusive Stacking = "exclusive"
+)
+
+// Target selects the products of the promotion, a product is selected when it
+// matches any of the lists, the categories include all their descendants
+type Target struct {
+	ProductIDs  []string `json:"productIds,omitempty"`
+	CategoryIDs []string `json:"categoryIds,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+func (target Target) IsEmpty() bool {
+	return len(target.ProductIDs) == 0 && len(target.CategoryIDs) == 0 && len(target.Tags) == 0
+}
+
+// Promotion is active from StartsAt until right before EndsAt, a nil time leaves
+// that side of the window open, the promotions with the highest Priority go first
+type Promotion struct {
+	ID         *string         `json:"id"`
+	Name       *string         `json:"name" validate:"required"`
+	Kind       Kind            `json:"kind" validate:"oneof=percentage fixed"`
+	Percentage *float64        `json:"percentage,omitempty"`
+	Amount     *money.Money    `json:"amount,omitempty"`
+	Target     Target          `json:"target"`
+	StartsAt   *timestamp.Time `json:"startsAt,omitempty"`
+	EndsAt     *timestamp.Time `json:"endsAt,omitempty"`
+	Priority   int             `json:"priority"`
+	Stacking   Stacking        `json:"stacking" validate:"oneof=stackable exclusive"`
+}
+
+func (promotion *Promotion) IsActive(at time.Time) bool {
+	if promotion.StartsAt != nil && at.Before(promotion.StartsAt.Time) {
+		return false
+	}
+
+	return promotion.EndsAt == nil || at.Before(promotion.EndsAt.Time)
+}
+
+// Discount returns how much the promotion takes from the price, never more than the price,
+// nil when a fixed promotion is in a different currency than the price or when the
+// promotion is incomplete, see Validate
+func (promotion *Promotion) Discount(price *money.Money) *money.Money {
+	var discount *money.Money
+
+	if price == nil {
+		return nil
+	}
+
+	switch promotion.Kind {
+	case Percentage:
+		if promotion.Percentage == nil {
+			return nil
+		}
+
+		discount = price.Multiply(*promotion.Percentage / 100)
+	case Fixed:
+		if promotion.Amount == nil || promotion.Amount.Currency != price.Currency {
+			return nil
+		}
+
+		discount = money.New(promotion.Amount.Amount, price.Currency)
+	default:
+		return nil
+	}
+
+	if discount.Amount > price.Amount {
+		discount = money.New(price.Amount, price.Currency)
+	}
+
+	return discount
+}
+
+var validator = validation.New(checkPromotion)
+
+// Validate returns validation.Errors when the promotion is not valid
+func Validate(promotion *Promotion) error {
+	return validator.Validate(promotion)
+}
+
+func checkPromotion(value interface{}) validation.Errors {
+	promotion := value.(*Promotion)
+	errs := validation.NotBlank("name", promotion.Name)
+
+	switch promotion.Kind {
+	case Percentage:
+		if promotion.Percentage == nil || *promotion.Percentage <= 0 || *promotion.Percentage > 100 {
+			errs = append(errs, validation.NewFieldError("percentage", "range", "must be greater than 0 and at most 100"))
+		}
+	case Fixed:
+		if promotion.Amount == nil || promotion.Amount.Amount <= 0 || !promotion.Amount.Currency.Valid() {
+			errs = append(errs, validation.NewFieldError("amount", "gt", "must be a positive amount"))
+		}
+	}
+
+	if promotion.Target.IsEmpty() {
+		errs = append(errs, validation.NewFieldError("target", "required", "must select some products"))
+	}
+
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.StartsAt.Before(promotion.EndsAt.Time) {
+		errs = append(errs, validation.NewFieldError("endsAt", "after", "must be after startsAt"))
+	}
+
+	return errs
+}
+
+// PromotionRepository implementations reject the promotions that are not valid
+type PromotionRepository interface {
+	Store(*Promotion) error
+	Update(ID *string, promotion *Promotion) error
+	Find(ID *string) (*Promotion, error)
+	Remove(ID *string) error
+	All() ([]*Promotion, error)
+
+	// Active returns the promotions whose window contains the given time
+	Active(at time.Time) ([]*Promotion, error)
+}
+
+// NotFoundError is returned when the requested promotion does not exist
+type NotFoundError struct {
+	ID string
+}
+
+func (err NotFoundError) Error() string {
+	return fmt.Sprintf("the promotion \"%s\" does not exist", err.ID)
+}
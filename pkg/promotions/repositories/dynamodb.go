@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/dynamo"
+	"github.com/alejo-lapix/products-go/pkg/promotions"
+	"github.com/alejo-lapix/products-go/pkg/timestamp"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+type DynamoDBPromotionRepository struct {
+	DynamoDB  *dynamodb.DynamoDB
+	tableName *string
+}
+
+func NewDynamoDBPromotionRepository(db *dynamodb.DynamoDB) *DynamoDBPromotionRepository {
+	return &DynamoDBPromotionRepository{
+		DynamoDB:  db,
+		tableName: aws.String("promotions"),
+	}
+}
+
+func (repository *DynamoDBPromotionRepository) Store(promotion *promotions.Promotion) error {
+	if err := promotions.Validate(promotion); err != nil {
+		return err
+	}
+
+	item, err := dynamodbattribute.MarshalMap(promotion)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+		Item:                item,
+		TableName:           repository.tableName,
+	})
+
+	return err
+}
+
+func (repository *DynamoDBPromotionRepository) Update(ID *string, promotion *promotions.Promotion) error {
+	if err := promotions.Validate(promotion); err != nil {
+		return err
+	}
+
+	item, err := dynamodbattribute.MarshalMap(promotion)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.DynamoDB.PutItem(&dynamodb.PutItemInput{
+		ConditionExpression:       aws.String("id = :id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":id": {S: ID}},
+		Item:                      item,
+		TableName:                 repository.tableName,
+	})
+
+	return err
+}
+
+func (repository *DynamoDBPromotionRepository) Find(ID *string) (*promotions.Promotion, error) {
+	output, err := repository.DynamoDB.GetItem(&dynamodb.GetItemInput{
+		Key:       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+		TableName: repository.tableName,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	promotion := &promotions.Promotion{}
+
+	if err = dynamodbattribute.UnmarshalMap(output.Item, promotion); err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (repository *DynamoDBPromotionRepository) Remove(ID *string) error {
+	_, err := repository.DynamoDB.DeleteItem(&dynamodb.DeleteItemInput{
+		Key:       map[string]*dynamodb.AttributeValue{"id": {S: ID}},
+		TableName: repository.tableName,
+	})
+
+	return err
+}
+
+func (repository *DynamoDBPromotionRepository) All() ([]*promotions.Promotion, error) {
+	return repository.scan(&dynamodb.ScanInput{TableName: repository.tableName})
+}
+
+// Active compares the windows in DynamoDB, the times are sortable strings
+func (repository *DynamoDBPromotionRepository) Active(at time.Time) ([]*promotions.Promotion, error) {
+	return repository.scan(&dynamodb.ScanInput{
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":at": {S: aws.String(timestamp.Format(at))}},
+		FilterExpression: aws.String("(attribute_not_exists(startsAt) OR startsAt <= :at)" +
+			" AND (attribute_not_exists(endsAt) OR endsAt > :at)"),
+		TableName: repository.tableName,
+	})
+}
+
+func (repository *DynamoDBPromotionRepository) scan(input *dynamodb.ScanInput) ([]*promotions.Promotion, error) {
+	items := make([]*promotions.Promotion, 0)
+
+	if err := dynamo.Scan(repository.DynamoDB, input, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
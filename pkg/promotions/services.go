@@ -0,0 +1,68 @@
+package promotions
+
+import "github.com/alejo-lapix/products-go/pkg/ids"
+
+type PromotionService struct {
+	Repository PromotionRepository
+	IDs        ids.Generator
+}
+
+func NewPromotionService(repository PromotionRepository) *PromotionService {
+	return &PromotionService{Repository: repository, IDs: ids.UUIDGenerator{}}
+}
+
+// NewPromotion gives a new ID to the promotion before storing it
+func (service *PromotionService) NewPromotion(promotion *Promotion) (*Promotion, error) {
+	id := service.IDs.NewID()
+	promotion.ID = &id
+
+	if err := Validate(promotion); err != nil {
+		return nil, err
+	}
+
+	if err := service.Repository.Store(promotion); err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (service *PromotionService) FindPromotion(ID *string) (*Promotion, error) {
+	promotion, err := service.Repository.Find(ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if promotion == nil {
+		return nil, NotFoundError{ID: *ID}
+	}
+
+	return promotion, nil
+}
+
+func (service *PromotionService) UpdatePromotion(ID *string, promotion *Promotion) (*Promotion, error) {
+	if _, err := service.FindPromotion(ID); err != nil {
+		return nil, err
+	}
+
+	promotion.ID = ID
+
+	if err := Validate(promotion); err != nil {
+		return nil, err
+	}
+
+	if err := service.Repository.Update(ID, promotion); err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (service *PromotionService) RemovePromotion(ID *string) error {
+	if _, err := service.FindPromotion(ID); err != nil {
+		return err
+	}
+
+	return service.Repository.Remove(ID)
+}
@@ -0,0 +1,77 @@
+package rates
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+)
+
+// Provider gives how many units of the currency "to" are worth one unit of "from"
+type Provider interface {
+	Rate(from, to money.Currency) (float64, error)
+}
+
+// UnknownRateError is returned when there is no rate between the currencies
+type UnknownRateError struct {
+	From money.Currency
+	To   money.Currency
+}
+
+func (err UnknownRateError) Error() string {
+	return fmt.Sprintf("there is no exchange rate from %s to %s", err.From, err.To)
+}
+
+// Table has the value of one unit of the Base currency in every other currency,
+// the rates between two currencies that are not the base are derived from them
+type Table struct {
+	Base  money.Currency             `json:"base"`
+	Rates map[money.Currency]float64 `json:"rates"`
+}
+
+func (table *Table) rate(currency money.Currency) (float64, bool) {
+	if currency == table.Base {
+		return 1, true
+	}
+
+	rate, ok := table.Rates[currency]
+
+	return rate, ok && rate > 0
+}
+
+func (table *Table) Rate(from, to money.Currency) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	fromRate, fromOK := table.rate(from)
+	toRate, toOK := table.rate(to)
+
+	if !fromOK || !toOK {
+		return 0, UnknownRateError{From: from, To: to}
+	}
+
+	return toRate / fromRate, nil
+}
+
+// LoadFile reads a JSON table like {"base": "USD", "rates": {"COP": 3400.5}}
+func LoadFile(path string) (*Table, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	table := &Table{}
+
+	if err = json.Unmarshal(data, table); err != nil {
+		return nil, err
+	}
+
+	if !table.Base.Valid() {
+		return nil, fmt.Errorf("the exchange rates file \"%s\" does not have a valid base currency", path)
+	}
+
+	return table, nil
+}
@@ -0,0 +1,56 @@
+package rates
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/alejo-lapix/products-go/pkg/money"
+)
+
+func TestTable_Rate(t *testing.T) {
+	table := &Table{Base: "USD", Rates: map[money.Currency]float64{"COP": 4000, "EUR": 0.8}}
+	tests := []struct {
+		name     string
+		from, to money.Currency
+		want     float64
+		wantErr  bool
+	}{
+		{name: "From the base", from: "USD", to: "COP", want: 4000},
+		{name: "To the base", from: "EUR", to: "USD", want: 1.25},
+		{name: "Between two currencies", from: "EUR", to: "COP", want: 5000},
+		{name: "Same currency", from: "GBP", to: "GBP", want: 1},
+		{name: "Unknown currency", from: "USD", to: "GBP", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := table.Rate(tt.from, tt.to)
+
+			if _, ok := err.(UnknownRateError); ok != tt.wantErr {
+				t.Fatalf("Rate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Rate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "rates")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(file.Name())
+	_, _ = file.WriteString(`{"base": "USD", "rates": {"COP": 4000}}`)
+	_ = file.Close()
+	table, err := LoadFile(file.Name())
+
+	if err != nil || table.Base != "USD" || table.Rates["COP"] != 4000 {
+		t.Errorf("LoadFile() = %v, error = %v", table, err)
+	}
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alejo-lapix/products-go/pkg/clock"
+	"github.com/alejo-lapix/products-go/pkg/dynamo"
+	"github.com/alejo-lapix/products-go/pkg/money"
+	"github.com/alejo-lapix/products-go/pkg/rates"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+const defaultRatesTTL = time.Minute
+
+// DynamoDBRateProvider reads the rates from a table with one item per currency,
+// like {"currency": "COP", "base": "USD", "rate": 3400.5}, all of them must
+// share the same base, Rate keeps the table for TTL, a zero TTL reads it every time
+type DynamoDBRateProvider struct {
+	DynamoDB  *dynamodb.DynamoDB
+	TTL       time.Duration
+	Clock     clock.Clock
+	tableName *string
+	mutex     sync.Mutex
+	table     *rates.Table
+	expiresAt time.Time
+}
+
+func NewDynamoDBRateProvider(db *dynamodb.DynamoDB) *DynamoDBRateProvider {
+	return &DynamoDBRateProvider{
+		DynamoDB:  db,
+		TTL:       defaultRatesTTL,
+		Clock:     clock.System,
+		tableName: aws.String("exchange-rates"),
+	}
+}
+
+type rateItem struct {
+	Currency money.Currency `json:"currency"`
+	Base     money.Currency `json:"base"`
+	Rate     float64        `json:"rate"`
+}
+
+// Table reads the whole table of rates, it is never cached
+func (provider *DynamoDBRateProvider) Table() (*rates.Table, error) {
+	items := make([]*rateItem, 0)
+
+	if err := dynamo.Scan(provider.DynamoDB, &dynamodb.ScanInput{TableName: provider.tableName}, &items); err != nil {
+		return nil, err
+	}
+
+	table := &rates.Table{Rates: map[money.Currency]float64{}}
+
+	for _, item := range items {
+		if table.Base == "" {
+			table.Base = item.Base
+		}
+
+		if item.Base != table.Base {
+			return nil, fmt.Errorf("the rate of %s is based on %s instead of %s", item.Currency, item.Base, table.Base)
+		}
+
+		table.Rates[item.Currency] = item.Rate
+	}
+
+	return table, nil
+}
+
+func (provider *DynamoDBRateProvider) Rate(from, to money.Currency) (float64, error) {
+	table, err := provider.cached()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return table.Rate(from, to)
+}
+
+// cached reads the table again once the previous one expired
+func (provider *DynamoDBRateProvider) cached() (*rates.Table, error) {
+	if provider.TTL <= 0 {
+		return provider.Table()
+	}
+
+	now := provider.now()
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	if provider.table != nil && now.Before(provider.expiresAt) {
+		return provider.table, nil
+	}
+
+	table, err := provider.Table()
+
+	if err != nil {
+		return nil, err
+	}
+
+	provider.table = table
+	provider.expiresAt = now.Add(provider.TTL)
+
+	return table, nil
+}
+
+func (provider *DynamoDBRateProvider) now() time.Time {
+	if provider.Clock == nil {
+		return clock.System.Now()
+	}
+
+	return provider.Clock.Now()
+}
@@ -0,0 +1,86 @@
+package timestamp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Layout always has the same width once the time is in UTC,
+// so the stored values can be compared as strings by DynamoDB
+const Layout = "2006-01-02T15:04:05.000Z07:00"
+
+// Time is stored as a sortable string, the values written before
+// this type existed used time.RFC3339 and are still accepted
+type Time struct {
+	time.Time
+}
+
+func New(at time.Time) *Time {
+	return &Time{Time: at.UTC()}
+}
+
+func Parse(value string) (*Time, error) {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return New(parsed), nil
+}
+
+// Format returns the time in the representation used to store it
+func Format(at time.Time) string {
+	return at.UTC().Format(Layout)
+}
+
+func (at Time) String() string {
+	return Format(at.Time)
+}
+
+func (at Time) MarshalJSON() ([]byte, error) {
+	return json.Marshal(at.String())
+}
+
+func (at *Time) UnmarshalJSON(data []byte) error {
+	var value string
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := Parse(value)
+
+	if err != nil {
+		return err
+	}
+
+	*at = *parsed
+
+	return nil
+}
+
+func (at Time) MarshalDynamoDBAttributeValue(value *dynamodb.AttributeValue) error {
+	formatted := at.String()
+	value.S = &formatted
+
+	return nil
+}
+
+func (at *Time) UnmarshalDynamoDBAttributeValue(value *dynamodb.AttributeValue) error {
+	if value.S == nil {
+		return nil
+	}
+
+	parsed, err := Parse(*value.S)
+
+	if err != nil {
+		return err
+	}
+
+	*at = *parsed
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package timestamp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+type record struct {
+	CreatedAt *Time `json:"createdAt"`
+}
+
+func TestTime_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Old RFC3339 records", input: "2019-08-20T10:15:00-05:00", want: "2019-08-20T15:15:00.000Z"},
+		{name: "Stored records", input: "2019-08-20T15:15:00.120Z", want: "2019-08-20T15:15:00.120Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fromJSON := &record{}
+
+			if err := json.Unmarshal([]byte(`{"createdAt":"`+tt.input+`"}`), fromJSON); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+
+			item, _ := dynamodbattribute.MarshalMap(map[string]string{"createdAt": tt.input})
+			fromDynamoDB := &record{}
+
+			if err := dynamodbattribute.UnmarshalMap(item, fromDynamoDB); err != nil {
+				t.Fatalf("UnmarshalDynamoDBAttributeValue() error = %v", err)
+			}
+
+			if fromJSON.CreatedAt.String() != tt.want || fromDynamoDB.CreatedAt.String() != tt.want {
+				t.Errorf("got = %s and %s, want %s", fromJSON.CreatedAt, fromDynamoDB.CreatedAt, tt.want)
+			}
+		})
+	}
+}
+
+func TestTime_Marshal(t *testing.T) {
+	at := New(time.Date(2019, 8, 20, 10, 15, 0, 0, time.FixedZone("COT", -5*3600)))
+	item, err := dynamodbattribute.MarshalMap(&record{CreatedAt: at})
+
+	if err != nil || *item["createdAt"].S != "2019-08-20T15:15:00.000Z" {
+		t.Errorf("MarshalDynamoDBAttributeValue() = %v, error = %v", item["createdAt"], err)
+	}
+
+	data, err := json.Marshal(&record{CreatedAt: at})
+
+	if err != nil || string(data) != `{"createdAt":"2019-08-20T15:15:00.000Z"}` {
+		t.Errorf("MarshalJSON() = %s, error = %v", data, err)
+	}
+}
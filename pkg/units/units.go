@@ -0,0 +1,168 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// Dimension groups the units that can be converted between them
+type Dimension string
+
+const (
+	Mass   Dimension = "mass"
+	Volume Dimension = "volume"
+	Length Dimension = "length"
+	Count  Dimension = "count"
+)
+
+// Unit is registered with its canonical Symbol, Factor is the
+// amount of the base unit of the dimension in one unit
+type Unit struct {
+	Symbol    string
+	Name      string
+	Dimension Dimension
+	Factor    float64
+}
+
+// References are the units used to compare the prices, the
+// price per kilogram, per litre, per metre or per unit
+var References = map[Dimension]string{
+	Mass:   "kg",
+	Volume: "l",
+	Length: "m",
+	Count:  "unit",
+}
+
+var (
+	mutex   sync.RWMutex
+	symbols = map[string]*Unit{}
+	aliases = map[string]*Unit{}
+)
+
+func init() {
+	// The base units are the gram, the millilitre, the metre and the unit
+	Register(Unit{Symbol: "mg", Name: "milligram", Dimension: Mass, Factor: 0.001}, "milligrams")
+	Register(Unit{Symbol: "g", Name: "gram", Dimension: Mass, Factor: 1}, "gr", "grs", "grams", "gramo", "gramos")
+	Register(Unit{Symbol: "kg", Name: "kilogram", Dimension: Mass, Factor: 1000}, "kgs", "kilo", "kilos", "kilograms", "kilogramo", "kilogramos")
+	Register(Unit{Symbol: "lb", Name: "pound", Dimension: Mass, Factor: 453.59237}, "lbs", "pounds", "libra", "libras")
+	Register(Unit{Symbol: "oz", Name: "ounce", Dimension: Mass, Factor: 28.349523125}, "ounces", "onza", "onzas")
+	Register(Unit{Symbol: "ml", Name: "millilitre", Dimension: Volume, Factor: 1}, "cc", "cm3", "milliliter", "millilitres", "milliliters", "mililitro", "mililitros")
+	Register(Unit{Symbol: "cl", Name: "centilitre", Dimension: Volume, Factor: 10}, "centiliter", "centilitres", "centiliters")
+	Register(Unit{Symbol: "l", Name: "litre", Dimension: Volume, Factor: 1000}, "lt", "lts", "liter", "litres", "liters", "litro", "litros")
+	Register(Unit{Symbol: "gal", Name: "gallon", Dimension: Volume, Factor: 3785.411784}, "gallons", "galon", "galones")
+	Register(Unit{Symbol: "fl oz", Name: "fluid ounce", Dimension: Volume, Factor: 29.5735295625}, "floz", "fluid ounces")
+	Register(Unit{Symbol: "mm", Name: "millimetre", Dimension: Length, Factor: 0.001}, "millimeter", "millimetres", "millimeters", "milimetro", "milimetros")
+	Register(Unit{Symbol: "cm", Name: "centimetre", Dimension: Length, Factor: 0.01}, "centimeter", "centimetres", "centimeters", "centimetro", "centimetros")
+	Register(Unit{Symbol: "m", Name: "metre", Dimension: Length, Factor: 1}, "mt", "mts", "meter", "metres", "meters", "metro", "metros")
+	Register(Unit{Symbol: "in", Name: "inch", Dimension: Length, Factor: 0.0254}, "inches", "pulgada", "pulgadas")
+	Register(Unit{Symbol: "ft", Name: "foot", Dimension: Length, Factor: 0.3048}, "feet", "pie", "pies")
+	Register(Unit{Symbol: "unit", Name: "unit", Dimension: Count, Factor: 1}, "u", "un", "und", "units", "ea", "each", "pc", "pcs", "piece", "pieces", "unidad", "unidades")
+	Register(Unit{Symbol: "dozen", Name: "dozen", Dimension: Count, Factor: 12}, "dz", "doz", "dozens", "docena", "docenas")
+}
+
+// Register adds the unit or replaces the one with the same symbol, the
+// symbol, the name and the aliases are accepted by Lookup in any case
+func Register(unit Unit, names ...string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	registered := &unit
+	previous := symbols[unit.Symbol]
+	symbols[unit.Symbol] = registered
+
+	if previous != nil {
+		for name, current := range aliases {
+			if current == previous {
+				aliases[name] = registered
+			}
+		}
+	}
+
+	for _, name := range append([]string{unit.Symbol, unit.Name}, names...) {
+		if name != "" {
+			aliases[key(name)] = registered
+		}
+	}
+}
+
+// key ignores the case, the trailing dots and the repeated white spaces
+func key(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.TrimRight(strings.TrimSpace(name), "."))), " ")
+}
+
+// Lookup finds the unit by its symbol, its name or any of its aliases
+func Lookup(name string) (*Unit, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	if unit, ok := aliases[key(name)]; ok {
+		return unit, nil
+	}
+
+	return nil, UnknownUnitError{Unit: name}
+}
+
+// Normalize returns the canonical symbol of the unit, "Kilogramos" turns into "kg"
+func Normalize(name string) (string, error) {
+	unit, err := Lookup(name)
+
+	if err != nil {
+		return "", err
+	}
+
+	return unit.Symbol, nil
+}
+
+// Convert changes the quantity from one unit to another of the same dimension
+func Convert(quantity float64, from, to string) (float64, error) {
+	source, err := Lookup(from)
+
+	if err != nil {
+		return 0, err
+	}
+
+	target, err := Lookup(to)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if source.Dimension != target.Dimension {
+		return 0, IncompatibleUnitsError{From: source.Symbol, To: target.Symbol}
+	}
+
+	// Removes the noise of the float operations, like money does
+	return math.Round(quantity*source.Factor/target.Factor*1e9) / 1e9, nil
+}
+
+// Reference returns the unit used to compare the prices of the given one
+func Reference(name string) (*Unit, error) {
+	unit, err := Lookup(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Lookup(References[unit.Dimension])
+}
+
+// UnknownUnitError is returned when the unit is not registered
+type UnknownUnitError struct {
+	Unit string
+}
+
+func (err UnknownUnitError) Error() string {
+	return fmt.Sprintf("the unit \"%s\" is not registered", err.Unit)
+}
+
+// IncompatibleUnitsError is returned when the units measure different dimensions
+type IncompatibleUnitsError struct {
+	From string
+	To   string
+}
+
+func (err IncompatibleUnitsError) Error() string {
+	return fmt.Sprintf("\"%s\" can not be converted to \"%s\"", err.From, err.To)
+}
@@ -0,0 +1,69 @@
+package units
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "kg", want: "kg"},
+		{name: "Kg", want: "kg"},
+		{name: " Kilogram ", want: "kg"},
+		{name: "kilogramos", want: "kg"},
+		{name: "Lts.", want: "l"},
+		{name: "Fl  Oz", want: "fl oz"},
+		{name: "und", want: "unit"},
+		{name: "bolsa", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.name)
+
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Normalize() = %s, error = %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		from     string
+		to       string
+		want     float64
+		wantErr  bool
+	}{
+		{name: "Grams to kilograms", quantity: 500, from: "g", to: "kg", want: 0.5},
+		{name: "Pounds to grams", quantity: 1, from: "lb", to: "g", want: 453.59237},
+		{name: "Millilitres to litres", quantity: 750, from: "ml", to: "Litro", want: 0.75},
+		{name: "Centimetres to metres", quantity: 30, from: "cm", to: "m", want: 0.3},
+		{name: "Dozens to units", quantity: 2, from: "docena", to: "unit", want: 24},
+		{name: "Different dimensions", quantity: 1, from: "kg", to: "l", wantErr: true},
+		{name: "Unknown unit", quantity: 1, from: "bolsa", to: "kg", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.quantity, tt.from, tt.to)
+
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Convert() = %v, error = %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(Unit{Symbol: "qq", Name: "quintal", Dimension: Mass, Factor: 50000}, "quintales")
+
+	if got, err := Convert(2, "Quintales", "kg"); err != nil || got != 100 {
+		t.Errorf("Convert() = %v, error = %v, want 100", got, err)
+	}
+
+	if unit, err := Reference("qq"); err != nil || unit.Symbol != "kg" {
+		t.Errorf("Reference() = %v, error = %v, want kg", unit, err)
+	}
+}
@@ -0,0 +1,158 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// FieldError describes why the value of a field is not valid, Path uses
+// the JSON names of the fields, e.g. "unitOfMeasurement.quantity"
+type FieldError struct {
+	Path    string `json:"path"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewFieldError(path, code, message string) *FieldError {
+	return &FieldError{Path: path, Code: code, Message: message}
+}
+
+func (err *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Path, err.Message)
+}
+
+// Errors is the error returned by a Validator, it has all the problems found
+type Errors []*FieldError
+
+func (errs Errors) Error() string {
+	messages := make([]string, len(errs))
+
+	for index, err := range errs {
+		messages[index] = err.Error()
+	}
+
+	return strings.Join(messages, ", ")
+}
+
+// Rule checks the given value, it returns nothing when the value is valid
+type Rule func(value interface{}) Errors
+
+// Validator runs the `validate` tags of the structs and the registered rules
+type Validator struct {
+	mutex sync.RWMutex
+	rules []Rule
+}
+
+func New(rules ...Rule) *Validator {
+	return &Validator{rules: rules}
+}
+
+// Register adds a rule that runs after the ones already registered
+func (validator *Validator) Register(rule Rule) {
+	validator.mutex.Lock()
+	defer validator.mutex.Unlock()
+
+	validator.rules = append(validator.rules, rule)
+}
+
+// Validate returns Errors when the value is not valid, nil otherwise
+func (validator *Validator) Validate(value interface{}) error {
+	validator.mutex.RLock()
+	defer validator.mutex.RUnlock()
+
+	errs := Struct(value)
+
+	for _, rule := range validator.rules {
+		errs = append(errs, rule(value)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+var tags = newTagValidator()
+
+func newTagValidator() *validator.Validate {
+	instance := validator.New()
+	instance.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+
+		if name == "" || name == "-" {
+			return field.Name
+		}
+
+		return name
+	})
+
+	return instance
+}
+
+// Struct runs only the `validate` tags of the given struct
+func Struct(value interface{}) Errors {
+	err := tags.Struct(value)
+
+	if err == nil {
+		return Errors{}
+	}
+
+	fieldErrors, ok := err.(validator.ValidationErrors)
+
+	if !ok {
+		return Errors{NewFieldError("", "invalid", err.Error())}
+	}
+
+	errs := make(Errors, len(fieldErrors))
+
+	for index, fieldError := range fieldErrors {
+		errs[index] = NewFieldError(path(fieldError.Namespace()), fieldError.Tag(), message(fieldError))
+	}
+
+	return errs
+}
+
+// path removes the name of the root struct from the namespace
+func path(namespace string) string {
+	parts := strings.SplitN(namespace, ".", 2)
+
+	if len(parts) < 2 {
+		return namespace
+	}
+
+	return parts[1]
+}
+
+func message(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fieldError.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fieldError.Param())
+	case "lt":
+		return fmt.Sprintf("must be lower than %s", fieldError.Param())
+	case "lte":
+		return fmt.Sprintf("must be lower than or equal to %s", fieldError.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", fieldError.Param())
+	}
+
+	return fmt.Sprintf("failed the \"%s\" validation", fieldError.Tag())
+}
+
+// NotBlank fails when the value is present but only has white spaces,
+// the `required` tag already covers the missing values
+func NotBlank(path string, value *string) Errors {
+	if value != nil && strings.TrimSpace(*value) == "" {
+		return Errors{NewFieldError(path, "blank", "can not be blank")}
+	}
+
+	return Errors{}
+}
@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+type measurement struct {
+	Quantity *float64 `json:"quantity" validate:"required,gt=0"`
+}
+
+type item struct {
+	Name        *string      `json:"name" validate:"required"`
+	Price       *float64     `json:"price" validate:"required,gte=0"`
+	Measurement *measurement `json:"unitOfMeasurement"`
+}
+
+func s(input string) *string {
+	return &input
+}
+
+func f(input float64) *float64 {
+	return &input
+}
+
+func paths(err error) []string {
+	result := make([]string, 0)
+
+	if err == nil {
+		return result
+	}
+
+	for _, fieldError := range err.(Errors) {
+		result = append(result, fieldError.Path+" "+fieldError.Code)
+	}
+
+	return result
+}
+
+func TestValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value *item
+		rules []Rule
+		want  []string
+	}{
+		{
+			name:  "Valid item",
+			value: &item{Name: s("Drill"), Price: f(10)},
+			want:  []string{},
+		},
+		{
+			name:  "Uses the JSON names in the path",
+			value: &item{Price: f(-1), Measurement: &measurement{Quantity: f(0)}},
+			want:  []string{"name required", "price gte", "unitOfMeasurement.quantity gt"},
+		},
+		{
+			name:  "Runs the registered rules",
+			value: &item{Name: s("  "), Price: f(1)},
+			rules: []Rule{func(value interface{}) Errors { return NotBlank("name", value.(*item).Name) }},
+			want:  []string{"name blank"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := New()
+			for _, rule := range tt.rules {
+				validator.Register(rule)
+			}
+			if got := paths(validator.Validate(tt.value)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}